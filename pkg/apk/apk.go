@@ -110,8 +110,12 @@ func PKGINFOFromAPK(r io.Reader) (*apk.Package, error) {
 		return nil, fmt.Errorf("failed to find .PKGINFO in apk")
 	}
 
+	return parsePKGINFO(tr)
+}
+
+func parsePKGINFO(r io.Reader) (*apk.Package, error) {
 	pkginfo := new(apk.Package)
-	loaded, err := ini.Load(tr)
+	loaded, err := ini.Load(r)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse INI data: %w", err)
 	}
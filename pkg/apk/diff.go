@@ -0,0 +1,223 @@
+package apk
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/chainguard-dev/go-apk/pkg/apk"
+)
+
+// File is a file, directory or link in the data section of an APK.
+type File struct {
+	Path string `json:"path"`
+	Mode string `json:"mode"`
+	Size int64  `json:"size"`
+	// Digest is the sha256 of a regular file's contents, or the target of a link.
+	Digest string `json:"digest,omitempty"`
+}
+
+// FileChange is a file present in both APKs whose mode or contents differ.
+type FileChange struct {
+	Path    string `json:"path"`
+	OldMode string `json:"oldMode"`
+	NewMode string `json:"newMode"`
+	OldSize int64  `json:"oldSize"`
+	NewSize int64  `json:"newSize"`
+}
+
+// ListChange holds the entries added to and removed from a list of package
+// metadata, such as the dependencies or provides.
+type ListChange struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+}
+
+// Diff describes the differences between two APKs.
+type Diff struct {
+	OldVersion   string       `json:"oldVersion"`
+	NewVersion   string       `json:"newVersion"`
+	Added        []File       `json:"added"`
+	Removed      []File       `json:"removed"`
+	Changed      []FileChange `json:"changed"`
+	Dependencies ListChange   `json:"dependencies"`
+	Provides     ListChange   `json:"provides"`
+	// SizeDelta is the change in the total size of the files in the package.
+	SizeDelta int64 `json:"sizeDelta"`
+}
+
+// DiffAPKs compares the files and .PKGINFO metadata of two APKs.
+func DiffAPKs(oldAPK, newAPK io.Reader) (*Diff, error) {
+	oldInfo, oldFiles, err := readAPK(oldAPK)
+	if err != nil {
+		return nil, fmt.Errorf("reading old apk: %w", err)
+	}
+	newInfo, newFiles, err := readAPK(newAPK)
+	if err != nil {
+		return nil, fmt.Errorf("reading new apk: %w", err)
+	}
+
+	d := &Diff{
+		OldVersion:   oldInfo.Version,
+		NewVersion:   newInfo.Version,
+		Added:        []File{},
+		Removed:      []File{},
+		Changed:      []FileChange{},
+		Dependencies: diffLists(oldInfo.Dependencies, newInfo.Dependencies),
+		Provides:     diffLists(oldInfo.Provides, newInfo.Provides),
+	}
+
+	for p, nf := range newFiles {
+		d.SizeDelta += nf.Size
+		of, ok := oldFiles[p]
+		switch {
+		case !ok:
+			d.Added = append(d.Added, nf)
+		case of.Mode != nf.Mode || of.Size != nf.Size || of.Digest != nf.Digest:
+			d.Changed = append(d.Changed, FileChange{
+				Path:    p,
+				OldMode: of.Mode,
+				NewMode: nf.Mode,
+				OldSize: of.Size,
+				NewSize: nf.Size,
+			})
+		}
+	}
+	for p, of := range oldFiles {
+		d.SizeDelta -= of.Size
+		if _, ok := newFiles[p]; !ok {
+			d.Removed = append(d.Removed, of)
+		}
+	}
+
+	sort.Slice(d.Added, func(i, j int) bool { return d.Added[i].Path < d.Added[j].Path })
+	sort.Slice(d.Removed, func(i, j int) bool { return d.Removed[i].Path < d.Removed[j].Path })
+	sort.Slice(d.Changed, func(i, j int) bool { return d.Changed[i].Path < d.Changed[j].Path })
+
+	return d, nil
+}
+
+// WriteText writes a human readable summary of the diff to w.
+func (d *Diff) WriteText(w io.Writer) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "version: %s -> %s\n", d.OldVersion, d.NewVersion)
+	fmt.Fprintf(&b, "size: %+d bytes\n", d.SizeDelta)
+	for _, f := range d.Added {
+		fmt.Fprintf(&b, "+ %s %s (%d bytes)\n", f.Mode, f.Path, f.Size)
+	}
+	for _, f := range d.Removed {
+		fmt.Fprintf(&b, "- %s %s (%d bytes)\n", f.Mode, f.Path, f.Size)
+	}
+	for _, c := range d.Changed {
+		var changes []string
+		if c.OldMode != c.NewMode {
+			changes = append(changes, fmt.Sprintf("mode %s -> %s", c.OldMode, c.NewMode))
+		}
+		if c.OldSize != c.NewSize {
+			changes = append(changes, fmt.Sprintf("size %d -> %d (%+d bytes)", c.OldSize, c.NewSize, c.NewSize-c.OldSize))
+		}
+		if len(changes) == 0 {
+			changes = append(changes, "contents")
+		}
+		fmt.Fprintf(&b, "~ %s: %s\n", c.Path, strings.Join(changes, ", "))
+	}
+	writeListChange(&b, "depends", d.Dependencies)
+	writeListChange(&b, "provides", d.Provides)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeListChange(b *strings.Builder, name string, lc ListChange) {
+	for _, a := range lc.Added {
+		fmt.Fprintf(b, "%s: + %s\n", name, a)
+	}
+	for _, r := range lc.Removed {
+		fmt.Fprintf(b, "%s: - %s\n", name, r)
+	}
+}
+
+// readAPK reads the .PKGINFO and the data files of an APK. Control files, such
+// as the signature and install scripts, are skipped.
+func readAPK(r io.Reader) (*apk.Package, map[string]File, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create gzip reader: %w", err)
+	}
+
+	var pkginfo *apk.Package
+	files := map[string]File{}
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read tar header: %w", err)
+		}
+
+		if hdr.Name == ".PKGINFO" {
+			pkginfo, err = parsePKGINFO(tr)
+			if err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+		if strings.HasPrefix(hdr.Name, ".") {
+			continue
+		}
+
+		f := File{
+			Path: strings.TrimSuffix(hdr.Name, "/"),
+			Mode: hdr.FileInfo().Mode().String(),
+			Size: hdr.Size,
+		}
+		switch hdr.Typeflag {
+		case tar.TypeReg:
+			h := sha256.New()
+			if _, err := io.Copy(h, tr); err != nil { //nolint:gosec // only hashing, nothing is written to disk
+				return nil, nil, fmt.Errorf("failed to read %s: %w", hdr.Name, err)
+			}
+			f.Digest = hex.EncodeToString(h.Sum(nil))
+		case tar.TypeSymlink, tar.TypeLink:
+			f.Digest = hdr.Linkname
+		}
+		files[f.Path] = f
+	}
+
+	if pkginfo == nil {
+		return nil, nil, fmt.Errorf("failed to find .PKGINFO in apk")
+	}
+
+	return pkginfo, files, nil
+}
+
+func diffLists(oldList, newList []string) ListChange {
+	lc := ListChange{Added: []string{}, Removed: []string{}}
+	seen := map[string]bool{}
+	for _, o := range oldList {
+		seen[o] = true
+	}
+	for _, n := range newList {
+		if !seen[n] {
+			lc.Added = append(lc.Added, n)
+		}
+		delete(seen, n)
+	}
+	for _, o := range oldList {
+		if seen[o] {
+			lc.Removed = append(lc.Removed, o)
+		}
+	}
+	sort.Strings(lc.Added)
+	sort.Strings(lc.Removed)
+	return lc
+}
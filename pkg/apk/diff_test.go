@@ -0,0 +1,106 @@
+package apk
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testFile struct {
+	name     string
+	mode     int64
+	contents string
+	linkname string
+}
+
+func testAPK(t *testing.T, pkginfo string, files []testFile) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+
+	files = append([]testFile{{name: ".PKGINFO", mode: 0o644, contents: pkginfo}}, files...)
+	for _, f := range files {
+		hdr := &tar.Header{Name: f.name, Mode: f.mode, Size: int64(len(f.contents)), Typeflag: tar.TypeReg}
+		if f.linkname != "" {
+			hdr.Typeflag, hdr.Linkname, hdr.Size = tar.TypeSymlink, f.linkname, 0
+		}
+		require.NoError(t, tw.WriteHeader(hdr))
+		_, err := tw.Write([]byte(f.contents))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+
+	return &buf
+}
+
+func TestDiffAPKs(t *testing.T) {
+	oldAPK := testAPK(t, `pkgname = foo
+pkgver = 1.0.0-r0
+depend = so:libc.so.6
+depend = bar
+provides = so:libfoo.so.1=1
+`, []testFile{
+		{name: "usr/bin/foo", mode: 0o755, contents: "old foo"},
+		{name: "usr/lib/libfoo.so.1", mode: 0o755, contents: "lib"},
+		{name: "usr/share/foo/readme", mode: 0o644, contents: "readme"},
+		{name: "usr/share/foo/config", mode: 0o644, contents: "config"},
+	})
+	newAPK := testAPK(t, `pkgname = foo
+pkgver = 1.1.0-r0
+depend = so:libc.so.6
+depend = baz
+provides = so:libfoo.so.2=2
+`, []testFile{
+		{name: "usr/bin/foo", mode: 0o755, contents: "new foo!"},
+		{name: "usr/lib/libfoo.so.2", mode: 0o755, contents: "lib"},
+		{name: "usr/lib/libfoo.so", mode: 0o777, linkname: "libfoo.so.2"},
+		{name: "usr/share/foo/readme", mode: 0o600, contents: "readme"},
+		{name: "usr/share/foo/config", mode: 0o644, contents: "CONFIG"},
+	})
+
+	got, err := DiffAPKs(oldAPK, newAPK)
+	require.NoError(t, err)
+
+	assert.Equal(t, &Diff{
+		OldVersion: "1.0.0-r0",
+		NewVersion: "1.1.0-r0",
+		Added: []File{
+			{Path: "usr/lib/libfoo.so", Mode: "Lrwxrwxrwx", Digest: "libfoo.so.2"},
+			{Path: "usr/lib/libfoo.so.2", Mode: "-rwxr-xr-x", Size: 3, Digest: "76b5a357391276b282a516f54f48ef3c207f46d8192dc58c208d5183d38415f8"},
+		},
+		Removed: []File{
+			{Path: "usr/lib/libfoo.so.1", Mode: "-rwxr-xr-x", Size: 3, Digest: "76b5a357391276b282a516f54f48ef3c207f46d8192dc58c208d5183d38415f8"},
+		},
+		Changed: []FileChange{
+			{Path: "usr/bin/foo", OldMode: "-rwxr-xr-x", NewMode: "-rwxr-xr-x", OldSize: 7, NewSize: 8},
+			{Path: "usr/share/foo/config", OldMode: "-rw-r--r--", NewMode: "-rw-r--r--", OldSize: 6, NewSize: 6},
+			{Path: "usr/share/foo/readme", OldMode: "-rw-r--r--", NewMode: "-rw-------", OldSize: 6, NewSize: 6},
+		},
+		Dependencies: ListChange{Added: []string{"baz"}, Removed: []string{"bar"}},
+		Provides:     ListChange{Added: []string{"so:libfoo.so.2=2"}, Removed: []string{"so:libfoo.so.1=1"}},
+		SizeDelta:    1,
+	}, got)
+
+	var buf bytes.Buffer
+	require.NoError(t, got.WriteText(&buf))
+	assert.Equal(t, `version: 1.0.0-r0 -> 1.1.0-r0
+size: +1 bytes
++ Lrwxrwxrwx usr/lib/libfoo.so (0 bytes)
++ -rwxr-xr-x usr/lib/libfoo.so.2 (3 bytes)
+- -rwxr-xr-x usr/lib/libfoo.so.1 (3 bytes)
+~ usr/bin/foo: size 7 -> 8 (+1 bytes)
+~ usr/share/foo/config: contents
+~ usr/share/foo/readme: mode -rw-r--r-- -> -rw-------
+depends: + baz
+depends: - bar
+provides: + so:libfoo.so.2=2
+provides: - so:libfoo.so.1=1
+`, buf.String())
+}
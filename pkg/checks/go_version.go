@@ -0,0 +1,387 @@
+package checks
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"go/version"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"chainguard.dev/melange/pkg/config"
+	"chainguard.dev/melange/pkg/util"
+	goapk "github.com/chainguard-dev/go-apk/pkg/apk"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/wolfi-dev/wolfictl/pkg/apk"
+	"github.com/wolfi-dev/wolfictl/pkg/lint"
+	"github.com/wolfi-dev/wolfictl/pkg/melange"
+	"golang.org/x/mod/modfile"
+)
+
+// goToolchainPackage matches the names of the Go toolchain packages, go and the version streams such as go-1.22
+var goToolchainPackage = regexp.MustCompile(`^go(-\d+\.\d+)?$`)
+
+// maxGoModSize bounds how much of a go.mod is read from an upstream source
+const maxGoModSize = 1 << 20
+
+type GoVersionOptions struct {
+	Client       *http.Client
+	Logger       *log.Logger
+	Dir          string
+	PackageNames []string
+	ApkIndexURL  string
+}
+
+// goMod is the Go version requirements of a go.mod file in a package's source.
+type goMod struct {
+	Path      string
+	Go        string
+	Toolchain string
+}
+
+func NewGoVersion() *GoVersionOptions {
+	o := &GoVersionOptions{
+		Client: http.DefaultClient,
+		Logger: log.New(log.Writer(), "wolfictl check go-version: ", log.LstdFlags|log.Lmsgprefix),
+	}
+
+	return o
+}
+
+/*
+CheckGoVersion reads the go.mod files in the sources of each Go package and checks the go directive of each one is
+satisfied by the Go toolchain package the package builds with, either a version stream such as go-1.21 pinned in its
+environment or the latest go.  The toolchain directive only names a preferred toolchain so it is reported but not
+enforced.  Packages pinned to an older stream than the latest are logged, as the next upstream go directive bump is
+likely to break them.
+*/
+func (o *GoVersionOptions) CheckGoVersion(ctx context.Context) error {
+	existing, err := apk.New(o.Client, o.ApkIndexURL).GetApkPackages()
+	if err != nil {
+		return fmt.Errorf("failed to get APK packages from URL %s: %w", o.ApkIndexURL, err)
+	}
+	toolchains := goToolchains(existing)
+	latest, ok := toolchains["go"]
+	if !ok {
+		return fmt.Errorf("no package provides go in %s", o.ApkIndexURL)
+	}
+
+	packages, err := melange.ReadPackageConfigs(ctx, o.PackageNames, o.Dir)
+	if err != nil {
+		return fmt.Errorf("failed to read package configs: %w", err)
+	}
+
+	names := make([]string, 0, len(packages))
+	for name := range packages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	checkErrors := make(lint.EvalRuleErrors, 0)
+	for _, name := range names {
+		cfg := packages[name].Config
+		toolchain, usesGo := goToolchainOf(&cfg)
+		if !usesGo {
+			continue
+		}
+		available, ok := toolchains[toolchain]
+		if !ok {
+			addCheckError(&checkErrors, fmt.Errorf("package %s: builds with %s which is not in %s", name, toolchain, o.ApkIndexURL))
+			continue
+		}
+		if toolchain != "go" && version.Compare(available, latest) < 0 {
+			o.Logger.Printf("package %s: pinned to %s (%s), the latest go is %s", name, toolchain, available, latest)
+		}
+
+		mods, err := o.readGoMods(ctx, &cfg)
+		if err != nil {
+			addCheckError(&checkErrors, fmt.Errorf("package %s: %w", name, err))
+			continue
+		}
+		for _, err := range checkGoMods(toolchain, available, latest, mods) {
+			addCheckError(&checkErrors, fmt.Errorf("package %s: %w", name, err))
+		}
+		if len(mods) > 0 {
+			o.Logger.Printf("package %s: checked %d go.mod files against %s (%s)", name, len(mods), toolchain, available)
+		}
+	}
+
+	return checkErrors.WrapErrors()
+}
+
+// goToolchains returns the Go version, e.g. go1.22.5, provided by each Go toolchain package.  The version streams
+// provide go, so go resolves to the newest of them, or the package named go if that is newer.
+func goToolchains(pkgs map[string]*goapk.Package) map[string]string {
+	toolchains := map[string]string{}
+	setNewest := func(name, v string) {
+		v = "go" + strings.SplitN(v, "-r", 2)[0]
+		if !version.IsValid(v) {
+			return
+		}
+		if current, ok := toolchains[name]; !ok || version.Compare(v, current) > 0 {
+			toolchains[name] = v
+		}
+	}
+	for name, p := range pkgs {
+		if goToolchainPackage.MatchString(name) {
+			setNewest(name, p.Version)
+		}
+		for _, provides := range p.Provides {
+			if v, ok := strings.CutPrefix(provides, "go="); ok {
+				setNewest("go", v)
+			}
+		}
+	}
+	return toolchains
+}
+
+// goToolchainOf returns the Go toolchain package in the build environment of a package and whether it builds Go at
+// all.  Packages using the go pipelines without listing a toolchain get the latest go.
+func goToolchainOf(cfg *config.Configuration) (string, bool) {
+	for _, p := range cfg.Environment.Contents.Packages {
+		// drop any version constraint, e.g. go-1.21>=1.21.5
+		name := p
+		if i := strings.IndexAny(name, "=<>~"); i >= 0 {
+			name = name[:i]
+		}
+		if goToolchainPackage.MatchString(name) {
+			return name, true
+		}
+	}
+	for i := range cfg.Pipeline {
+		if strings.HasPrefix(cfg.Pipeline[i].Uses, "go/") {
+			return "go", true
+		}
+	}
+	return "", false
+}
+
+// checkGoMods returns an error for every go.mod that needs a newer Go than available.
+func checkGoMods(toolchain, available, latest string, mods []goMod) []error {
+	var errs []error
+	for _, m := range mods {
+		if m.Go == "" {
+			continue
+		}
+		required := "go" + m.Go
+		if version.Compare(required, available) <= 0 {
+			continue
+		}
+		msg := fmt.Sprintf("%s requires go %s but %s provides %s", m.Path, m.Go, toolchain, strings.TrimPrefix(available, "go"))
+		if m.Toolchain != "" {
+			msg += fmt.Sprintf(" (toolchain %s)", m.Toolchain)
+		}
+		if version.Compare(required, latest) > 0 {
+			msg += ", no go package is new enough"
+		}
+		errs = append(errs, errors.New(msg))
+	}
+	return errs
+}
+
+// readGoMods reads the go.mod files from the sources of the fetch and git-checkout pipelines of a package.  A source
+// that can't be read, such as a fetch of something other than a tarball or a failed clone, is logged and skipped so
+// the go.mod files of the other sources are still checked.
+func (o *GoVersionOptions) readGoMods(ctx context.Context, cfg *config.Configuration) ([]goMod, error) {
+	mutations, err := pipelineMutations(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var mods []goMod
+	for i := range cfg.Pipeline {
+		p := &cfg.Pipeline[i]
+		var found []goMod
+		switch p.Uses {
+		case "fetch":
+			found, err = o.fetchGoMods(ctx, p, mutations)
+		case "git-checkout":
+			found, err = o.gitGoMods(ctx, p, mutations)
+		default:
+			continue
+		}
+		if err != nil {
+			o.Logger.Printf("package %s: skipping %s pipeline: %v", cfg.Package.Name, p.Uses, err)
+			continue
+		}
+		mods = append(mods, found...)
+	}
+	return mods, nil
+}
+
+func (o *GoVersionOptions) fetchGoMods(ctx context.Context, p *config.Pipeline, m map[string]string) ([]goMod, error) {
+	uri, err := util.MutateStringFromMap(m, p.With["uri"])
+	if err != nil {
+		return nil, err
+	}
+
+	o.Logger.Printf("downloading %s", uri)
+
+	resp, err := download(ctx, o.Client, uri)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	mods, err := findGoMods(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect %s: %w", uri, err)
+	}
+	return mods, nil
+}
+
+// findGoMods reads the go.mod files from a compressed tarball.
+func findGoMods(r io.Reader) ([]goMod, error) {
+	dr, err := decompress(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var mods []goMod
+	tr := tar.NewReader(dr)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tar header: %w", err)
+		}
+		name := strings.TrimPrefix(path.Clean(hdr.Name), "./")
+		if hdr.Typeflag != tar.TypeReg || !isGoMod(name) {
+			continue
+		}
+
+		data, err := io.ReadAll(io.LimitReader(tr, maxGoModSize))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		mod, err := parseGoMod(name, data)
+		if err != nil {
+			return nil, err
+		}
+		mods = append(mods, mod)
+	}
+
+	sort.Slice(mods, func(i, j int) bool { return mods[i].Path < mods[j].Path })
+	return mods, nil
+}
+
+// gitGoMods shallow clones the tag or branch of a git-checkout pipeline into memory and reads its go.mod files.
+func (o *GoVersionOptions) gitGoMods(ctx context.Context, p *config.Pipeline, m map[string]string) ([]goMod, error) {
+	repository, err := util.MutateStringFromMap(m, p.With["repository"])
+	if err != nil {
+		return nil, err
+	}
+	if repository == "" {
+		return nil, fmt.Errorf("no repository to checkout")
+	}
+	// a shallow clone can only fetch a tag or branch, the default branch would likely have a different go.mod
+	if p.With["tag"] == "" && p.With["branch"] == "" && p.With["expected-commit"] != "" {
+		return nil, fmt.Errorf("%s is checked out at expected-commit %s without a tag or branch, which can't be cloned shallow", repository, p.With["expected-commit"])
+	}
+
+	opts := &git.CloneOptions{
+		URL:          repository,
+		Depth:        1,
+		SingleBranch: true,
+		Tags:         git.NoTags,
+	}
+	switch {
+	case p.With["tag"] != "":
+		tag, err := util.MutateStringFromMap(m, p.With["tag"])
+		if err != nil {
+			return nil, err
+		}
+		opts.ReferenceName = plumbing.NewTagReferenceName(tag)
+	case p.With["branch"] != "":
+		branch, err := util.MutateStringFromMap(m, p.With["branch"])
+		if err != nil {
+			return nil, err
+		}
+		opts.ReferenceName = plumbing.NewBranchReferenceName(branch)
+	}
+
+	o.Logger.Printf("cloning %s %s", repository, opts.ReferenceName.Short())
+
+	r, err := git.CloneContext(ctx, memory.NewStorage(), nil, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to clone %s: %w", repository, err)
+	}
+	head, err := r.Head()
+	if err != nil {
+		return nil, err
+	}
+	commit, err := r.CommitObject(head.Hash())
+	if err != nil {
+		return nil, err
+	}
+	files, err := commit.Files()
+	if err != nil {
+		return nil, err
+	}
+
+	var mods []goMod
+	err = files.ForEach(func(f *object.File) error {
+		if !isGoMod(f.Name) || f.Size > maxGoModSize {
+			return nil
+		}
+		contents, err := f.Contents()
+		if err != nil {
+			return err
+		}
+		mod, err := parseGoMod(f.Name, []byte(contents))
+		if err != nil {
+			return err
+		}
+		mods = append(mods, mod)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read go.mod files from %s: %w", repository, err)
+	}
+
+	sort.Slice(mods, func(i, j int) bool { return mods[i].Path < mods[j].Path })
+	return mods, nil
+}
+
+// isGoMod reports whether name is a go.mod that is part of the build, skipping vendored and test modules.
+func isGoMod(name string) bool {
+	parts := strings.Split(name, "/")
+	if parts[len(parts)-1] != "go.mod" {
+		return false
+	}
+	return !slices.ContainsFunc(parts, func(p string) bool {
+		return p == "vendor" || p == "testdata" || p == "node_modules"
+	})
+}
+
+func parseGoMod(name string, data []byte) (goMod, error) {
+	// the lax parser skips the toolchain directive, it is only used for go.mod files with directives newer than
+	// x/mod knows about
+	f, err := modfile.Parse(name, data, nil)
+	if err != nil {
+		f, err = modfile.ParseLax(name, data, nil)
+	}
+	if err != nil {
+		return goMod{}, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	mod := goMod{Path: name}
+	if f.Go != nil {
+		mod.Go = f.Go.Version
+	}
+	if f.Toolchain != nil {
+		mod.Toolchain = f.Toolchain.Name
+	}
+	return mod, nil
+}
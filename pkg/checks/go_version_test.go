@@ -0,0 +1,191 @@
+package checks
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"chainguard.dev/apko/pkg/build/types"
+	"chainguard.dev/melange/pkg/config"
+	goapk "github.com/chainguard-dev/go-apk/pkg/apk"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoVersion_goToolchains(t *testing.T) {
+	got := goToolchains(map[string]*goapk.Package{
+		"go":      {Name: "go", Version: "1.23.1-r0"},
+		"go-1.22": {Name: "go-1.22", Version: "1.22.7-r1"},
+		"go-1.21": {Name: "go-1.21", Version: "1.21.13-r2"},
+		"gobump":  {Name: "gobump", Version: "0.7.0-r0"},
+		"go-fips": {Name: "go-fips", Version: "1.22.0-r0"},
+	})
+	assert.Equal(t, map[string]string{"go": "go1.23.1", "go-1.22": "go1.22.7", "go-1.21": "go1.21.13"}, got)
+
+	// without a package named go it is resolved through the streams that provide it
+	got = goToolchains(map[string]*goapk.Package{
+		"go-1.23": {Name: "go-1.23", Version: "1.23.2-r0", Provides: []string{"go=1.23.2-r0", "cmd:go=1.23.2-r0"}},
+		"go-1.22": {Name: "go-1.22", Version: "1.22.8-r0", Provides: []string{"go=1.22.8-r0"}},
+		"go-fips": {Name: "go-fips", Version: "1.23.0-r0", Provides: []string{"go-fips=1.23.0-r0"}},
+	})
+	assert.Equal(t, map[string]string{"go": "go1.23.2", "go-1.23": "go1.23.2", "go-1.22": "go1.22.8"}, got)
+}
+
+func TestGoVersion_goToolchainOf(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        config.Configuration
+		want       string
+		wantUsesGo bool
+	}{
+		{
+			name: "pinned stream",
+			cfg: config.Configuration{
+				Environment: types.ImageConfiguration{Contents: types.ImageContents{Packages: []string{"busybox", "go-1.21>=1.21.5"}}},
+			},
+			want:       "go-1.21",
+			wantUsesGo: true,
+		},
+		{
+			name:       "go pipeline",
+			cfg:        config.Configuration{Pipeline: []config.Pipeline{{Uses: "fetch"}, {Uses: "go/build"}}},
+			want:       "go",
+			wantUsesGo: true,
+		},
+		{
+			name: "not go",
+			cfg: config.Configuration{
+				Environment: types.ImageConfiguration{Contents: types.ImageContents{Packages: []string{"gobump", "make"}}},
+				Pipeline:    []config.Pipeline{{Uses: "autoconf/make"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, usesGo := goToolchainOf(&tt.cfg)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantUsesGo, usesGo)
+		})
+	}
+}
+
+func TestGoVersion_checkGoMods(t *testing.T) {
+	errs := checkGoMods("go-1.21", "go1.21.13", "go1.23.1", []goMod{
+		{Path: "foo/go.mod", Go: "1.21"},
+		{Path: "foo/cmd/go.mod", Go: "1.21.13"},
+		{Path: "foo/tools/go.mod", Go: "1.22.0", Toolchain: "go1.22.5"},
+		{Path: "foo/next/go.mod", Go: "1.24"},
+		{Path: "foo/old/go.mod"},
+	})
+
+	require.Len(t, errs, 2)
+	assert.EqualError(t, errs[0], "foo/tools/go.mod requires go 1.22.0 but go-1.21 provides 1.21.13 (toolchain go1.22.5)")
+	assert.EqualError(t, errs[1], "foo/next/go.mod requires go 1.24 but go-1.21 provides 1.21.13, no go package is new enough")
+}
+
+func TestGoVersion_findGoMods(t *testing.T) {
+	var buf bytes.Buffer
+	testTarball(t, gzip.NewWriter(&buf), map[string]string{
+		"foo-1.0/go.mod":                               "module example.com/foo\n\ngo 1.22.0\n\ntoolchain go1.22.5\n",
+		"foo-1.0/tools/go.mod":                         "module example.com/foo/tools\n\ngo 1.21\n",
+		"foo-1.0/vendor/example.com/bar/go.mod":        "module example.com/bar\n\ngo 1.23\n",
+		"foo-1.0/internal/testdata/broken/go.mod":      "not a go.mod",
+		"foo-1.0/main.go":                              "package main",
+		"foo-1.0/web/node_modules/some-package/go.mod": "module example.com/some-package\n\ngo 1.23\n",
+	})
+
+	got, err := findGoMods(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, []goMod{
+		{Path: "foo-1.0/go.mod", Go: "1.22.0", Toolchain: "go1.22.5"},
+		{Path: "foo-1.0/tools/go.mod", Go: "1.21"},
+	}, got)
+}
+
+// testGoModRepo creates a git repository with a go.mod tagged v1.0.0.
+func testGoModRepo(t *testing.T, goMod string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	r, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	w, err := r.Worktree()
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0o600))
+	_, err = w.Add("go.mod")
+	require.NoError(t, err)
+	sig := &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()}
+	commit, err := w.Commit("first", &git.CommitOptions{Author: sig})
+	require.NoError(t, err)
+	_, err = r.CreateTag("v1.0.0", commit, nil)
+	require.NoError(t, err)
+
+	return dir
+}
+
+func TestGoVersion_gitGoMods(t *testing.T) {
+	dir := testGoModRepo(t, "module example.com/foo\n\ngo 1.22\n")
+
+	o := GoVersionOptions{Logger: log.New(io.Discard, "", 0)}
+	m := map[string]string{"${{package.version}}": "1.0.0", "${{vars.repository}}": dir}
+	p := &config.Pipeline{Uses: "git-checkout", With: map[string]string{"repository": "${{vars.repository}}", "tag": "v${{package.version}}"}}
+
+	got, err := o.gitGoMods(context.Background(), p, m)
+	require.NoError(t, err)
+	assert.Equal(t, []goMod{{Path: "go.mod", Go: "1.22"}}, got)
+
+	// the default branch may not be at the expected commit, so it isn't checked
+	p = &config.Pipeline{Uses: "git-checkout", With: map[string]string{"repository": dir, "expected-commit": "0123456789abcdef"}}
+	_, err = o.gitGoMods(context.Background(), p, m)
+	assert.EqualError(t, err, dir+" is checked out at expected-commit 0123456789abcdef without a tag or branch, which can't be cloned shallow")
+}
+
+func TestGoVersion_readGoMods(t *testing.T) {
+	var buf bytes.Buffer
+	testTarball(t, gzip.NewWriter(&buf), map[string]string{"foo-1.0.0/go.mod": "module example.com/foo\n\ngo 1.21\n"})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/foo-1.0.0.tar.gz":
+			_, _ = w.Write(buf.Bytes())
+		case "/foo-1.0.0.zip":
+			_, _ = w.Write([]byte("PK\x03\x04"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	dir := testGoModRepo(t, "module example.com/bar\n\ngo 1.22\n")
+
+	var logs bytes.Buffer
+	o := GoVersionOptions{Client: server.Client(), Logger: log.New(&logs, "", 0)}
+	cfg := &config.Configuration{
+		Package: config.Package{Name: "foo", Version: "1.0.0"},
+		Pipeline: []config.Pipeline{
+			{Uses: "fetch", With: map[string]string{"uri": server.URL + "/foo-${{package.version}}.tar.gz"}},
+			{Uses: "fetch", With: map[string]string{"uri": server.URL + "/foo-${{package.version}}.zip"}},
+			{Uses: "git-checkout", With: map[string]string{"repository": filepath.Join(dir, "missing"), "tag": "v${{package.version}}"}},
+			{Uses: "git-checkout", With: map[string]string{"repository": dir, "tag": "v${{package.version}}"}},
+			{Uses: "go/build"},
+		},
+	}
+
+	// the sources that can't be read are skipped without losing the go.mod files of the others
+	got, err := o.readGoMods(context.Background(), cfg)
+	require.NoError(t, err)
+	assert.Equal(t, []goMod{{Path: "foo-1.0.0/go.mod", Go: "1.21"}, {Path: "go.mod", Go: "1.22"}}, got)
+	assert.Contains(t, logs.String(), "package foo: skipping fetch pipeline: failed to inspect "+server.URL+"/foo-1.0.0.zip: unsupported archive")
+	assert.Contains(t, logs.String(), "package foo: skipping git-checkout pipeline: failed to clone "+filepath.Join(dir, "missing"))
+}
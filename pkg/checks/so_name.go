@@ -2,12 +2,15 @@ package checks
 
 import (
 	"debug/elf"
+	"errors"
 	"fmt"
+	"io/fs"
 	"log"
 	"net/http"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 
 	goapk "github.com/chainguard-dev/go-apk/pkg/apk"
@@ -133,12 +136,141 @@ func (o *SoNameOptions) diff(newPackageName string, newAPK NewApkPackage) error
 
 	err = o.checkSonamesMatch(existingSonameFiles, newSonameFiles)
 	if err != nil {
-		return fmt.Errorf("soname files differ, this can cause an ABI break.  Existing soname files %s, New soname files %s: %w", strings.Join(existingSonameFiles, ","), strings.Join(newSonameFiles, ","), err)
+		err = fmt.Errorf("soname files differ, this can cause an ABI break.  Existing soname files %s, New soname files %s: %w", strings.Join(existingSonameFiles, ","), strings.Join(newSonameFiles, ","), err)
+		if dependents := sonameDependents(p, o.ExistingPackages); len(dependents) > 0 {
+			err = fmt.Errorf("%w.  Packages that need rebuilding: %s", err, strings.Join(dependents, ","))
+		}
+		return err
+	}
+
+	// the soname is unchanged, so dependents will load the new library and need every symbol it used to export
+	removed, err := removedSymbols(dirExistingApk, dirNewApk)
+	if err != nil {
+		return fmt.Errorf("failed to compare shared library symbols: %w", err)
+	}
+	if len(removed) > 0 {
+		err = fmt.Errorf("shared libraries no longer export symbols, this can cause an ABI break without a soname change: %s", strings.Join(removed, ", "))
+		if dependents := sonameDependents(p, o.ExistingPackages); len(dependents) > 0 {
+			err = fmt.Errorf("%w.  Packages that need rebuilding: %s", err, strings.Join(dependents, ","))
+		}
+		return err
 	}
 
 	return nil
 }
 
+// removedSymbols returns the symbols, as soname: symbol, exported by a shared library below existingDir that the
+// library with the same soname below newDir no longer exports.
+func removedSymbols(existingDir, newDir string) ([]string, error) {
+	existing, err := exportedSymbols(existingDir)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := exportedSymbols(newDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []string
+	for soname, symbols := range existing {
+		newSymbols, ok := updated[soname]
+		if !ok {
+			// a changed or dropped soname is reported by checkSonamesMatch
+			continue
+		}
+		for sym := range symbols {
+			if !newSymbols[sym] {
+				removed = append(removed, fmt.Sprintf("%s: %s", soname, sym))
+			}
+		}
+	}
+	sort.Strings(removed)
+
+	return removed, nil
+}
+
+// exportedSymbols returns the dynamic symbols defined by each shared library below dir, keyed by DT_SONAME.  Versioned
+// symbols are named symbol@version.
+func exportedSymbols(dir string) (map[string]map[string]bool, error) {
+	libs := map[string]map[string]bool{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		ef, err := elf.Open(path)
+		if err != nil {
+			// not an ELF file
+			return nil
+		}
+		defer ef.Close()
+
+		sonames, err := ef.DynString(elf.DT_SONAME)
+		if err != nil || len(sonames) == 0 {
+			return nil
+		}
+
+		syms, err := ef.DynamicSymbols()
+		if err != nil && !errors.Is(err, elf.ErrNoSymbols) {
+			return fmt.Errorf("failed to read dynamic symbols of %s: %w", path, err)
+		}
+
+		exported := map[string]bool{}
+		for _, sym := range syms {
+			if sym.Section == elf.SHN_UNDEF {
+				continue
+			}
+			if bind := elf.ST_BIND(sym.Info); bind != elf.STB_GLOBAL && bind != elf.STB_WEAK {
+				continue
+			}
+			name := sym.Name
+			if sym.Version != "" {
+				name += "@" + sym.Version
+			}
+			exported[name] = true
+		}
+		libs[sonames[0]] = exported
+
+		return nil
+	})
+
+	return libs, err
+}
+
+// sonameDependents returns the names of the packages that depend on a shared library provided by pkg, and so need to
+// be rebuilt when its soname changes.
+func sonameDependents(pkg *goapk.Package, packages map[string]*goapk.Package) []string {
+	provided := map[string]bool{}
+	for _, p := range pkg.Provides {
+		if strings.HasPrefix(p, "so:") {
+			name, _, _ := strings.Cut(p, "=")
+			provided[name] = true
+		}
+	}
+	if len(provided) == 0 {
+		return nil
+	}
+
+	var dependents []string
+	for name, p := range packages {
+		if name == pkg.Name {
+			continue
+		}
+		for _, dep := range p.Dependencies {
+			if provided[dep] {
+				dependents = append(dependents, name)
+				break
+			}
+		}
+	}
+	sort.Strings(dependents)
+
+	return dependents
+}
+
 func (o *SoNameOptions) getSonameFiles(dir string) ([]string, error) {
 	reg := regexp.MustCompile(`\.so.(\d+\.)?(\d+\.)?(\*|\d+)`)
 
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	goapk "github.com/chainguard-dev/go-apk/pkg/apk"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -162,3 +163,28 @@ func TestSoNameOptions_checkSonamesSubFolders(t *testing.T) {
 
 	assert.Equal(t, "bar.so.1.2.3", got[0])
 }
+
+func TestSoNameOptions_sonameDependents(t *testing.T) {
+	foo := &goapk.Package{Name: "foo", Provides: []string{"so:libfoo.so.1=1", "cmd:foo=1.0.0-r0"}, Dependencies: []string{"so:libc.so.6"}}
+	packages := map[string]*goapk.Package{
+		"foo":     foo,
+		"bar":     {Name: "bar", Dependencies: []string{"so:libc.so.6", "so:libfoo.so.1"}},
+		"baz":     {Name: "baz", Dependencies: []string{"so:libfoo.so.1"}},
+		"cheese":  {Name: "cheese", Dependencies: []string{"so:libc.so.6"}},
+		"foo-dev": {Name: "foo-dev", Dependencies: []string{"foo"}},
+	}
+
+	assert.Equal(t, []string{"bar", "baz"}, sonameDependents(foo, packages))
+	assert.Empty(t, sonameDependents(packages["cheese"], packages))
+}
+
+func TestSoNameOptions_removedSymbols(t *testing.T) {
+	// both libraries have the soname libfoo.so.1, the new one drops bar and adds baz
+	got, err := removedSymbols(filepath.Join("testdata", "symbols", "old"), filepath.Join("testdata", "symbols", "new"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"libfoo.so.1: bar"}, got)
+
+	got, err = removedSymbols(filepath.Join("testdata", "symbols", "new"), filepath.Join("testdata", "symbols", "old"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"libfoo.so.1: baz"}, got)
+}
@@ -0,0 +1,215 @@
+package checks
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+
+	"chainguard.dev/melange/pkg/build"
+	"chainguard.dev/melange/pkg/config"
+	"chainguard.dev/melange/pkg/util"
+	"github.com/fatih/color"
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/wolfi-dev/wolfictl/pkg/lint"
+	"github.com/wolfi-dev/wolfictl/pkg/melange"
+)
+
+type SourcesOptions struct {
+	Client       *http.Client
+	Logger       *log.Logger
+	Dir          string
+	PackageNames []string
+}
+
+func NewSources() *SourcesOptions {
+	o := &SourcesOptions{
+		Client: http.DefaultClient,
+		Logger: log.New(log.Writer(), "wolfictl check sources: ", log.LstdFlags|log.Lmsgprefix),
+	}
+
+	return o
+}
+
+/*
+CheckSources fetches the sources declared by the fetch and git-checkout pipelines of each package and verifies they
+still match the expected-sha256, expected-sha512 and expected-commit pinned in the melange config.  This catches
+upstreams that have re-tagged a release or replaced a release tarball.
+*/
+func (o *SourcesOptions) CheckSources(ctx context.Context) error {
+	packages, err := melange.ReadPackageConfigs(ctx, o.PackageNames, o.Dir)
+	if err != nil {
+		return fmt.Errorf("failed to read package configs: %w", err)
+	}
+
+	names := make([]string, 0, len(packages))
+	for name := range packages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	checkErrors := make(lint.EvalRuleErrors, 0)
+	for _, name := range names {
+		cfg := packages[name].Config
+		mutations, err := pipelineMutations(&cfg)
+		if err != nil {
+			return err
+		}
+
+		for i := range cfg.Pipeline {
+			p := &cfg.Pipeline[i]
+			switch p.Uses {
+			case "fetch":
+				err = o.verifyFetchDigest(ctx, p, mutations)
+			case "git-checkout":
+				err = o.verifyGitRef(ctx, p, mutations)
+			default:
+				continue
+			}
+			if err != nil {
+				addCheckError(&checkErrors, fmt.Errorf("package %s: %w", name, err))
+			}
+		}
+	}
+
+	return checkErrors.WrapErrors()
+}
+
+// verifyFetchDigest downloads the uri of a fetch pipeline and compares its digest with the pinned expected-sha256 or
+// expected-sha512.
+func (o *SourcesOptions) verifyFetchDigest(ctx context.Context, p *config.Pipeline, m map[string]string) error {
+	uri, err := util.MutateStringFromMap(m, p.With["uri"])
+	if err != nil {
+		return err
+	}
+	if uri == "" {
+		return fmt.Errorf("no uri to fetch")
+	}
+
+	var h hash.Hash
+	var expected string
+	switch {
+	case p.With["expected-sha256"] != "":
+		h, expected = sha256.New(), p.With["expected-sha256"]
+	case p.With["expected-sha512"] != "":
+		h, expected = sha512.New(), p.With["expected-sha512"]
+	default:
+		return fmt.Errorf("no expected-sha256 or expected-sha512 for %s", uri)
+	}
+
+	o.Logger.Printf("downloading %s", uri)
+
+	resp, err := download(ctx, o.Client, uri)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if _, err := io.Copy(h, resp.Body); err != nil {
+		return fmt.Errorf("failed to read %s: %w", uri, err)
+	}
+
+	if actual := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(actual, expected) {
+		return fmt.Errorf("digest of %s is %s but %s is expected", uri, actual, expected)
+	}
+
+	o.Logger.Println(color.GreenString("digest of %s matches", uri))
+	return nil
+}
+
+// verifyGitRef lists the references of the repository of a git-checkout pipeline and checks that the tag exists and
+// still points at the pinned expected-commit.
+func (o *SourcesOptions) verifyGitRef(ctx context.Context, p *config.Pipeline, m map[string]string) error {
+	repository, err := util.MutateStringFromMap(m, p.With["repository"])
+	if err != nil {
+		return err
+	}
+	if repository == "" {
+		return fmt.Errorf("no repository to checkout")
+	}
+
+	tagValue := p.With["tag"]
+	if tagValue == "" {
+		// branches are expected to move, so there is nothing to verify
+		return nil
+	}
+
+	tag, err := util.MutateStringFromMap(m, tagValue)
+	if err != nil {
+		return err
+	}
+
+	o.Logger.Printf("listing references of %s", repository)
+
+	remote := git.NewRemote(memory.NewStorage(), &gitconfig.RemoteConfig{
+		Name: "origin",
+		URLs: []string{repository},
+	})
+	refs, err := remote.ListContext(ctx, &git.ListOptions{PeelingOption: git.AppendPeeled})
+	if err != nil {
+		return fmt.Errorf("failed to list references of %s: %w", repository, err)
+	}
+
+	// annotated tags point at a tag object, the commit is in the peeled reference
+	tagRef := "refs/tags/" + tag
+	var commit string
+	for _, ref := range refs {
+		switch ref.Name().String() {
+		case tagRef:
+			if commit == "" {
+				commit = ref.Hash().String()
+			}
+		case tagRef + "^{}":
+			commit = ref.Hash().String()
+		}
+	}
+
+	if commit == "" {
+		return fmt.Errorf("tag %s not found in %s", tag, repository)
+	}
+
+	if expected := p.With["expected-commit"]; expected != "" && commit != expected {
+		return fmt.Errorf("tag %s of %s points at %s but expected-commit is %s, the tag may have moved", tag, repository, commit, expected)
+	}
+
+	o.Logger.Println(color.GreenString("tag %s of %s matches", tag, repository))
+	return nil
+}
+
+// pipelineMutations returns the variables, such as ${{package.version}}, that can be substituted in the with
+// parameters of a package's pipelines.
+func pipelineMutations(cfg *config.Configuration) (map[string]string, error) {
+	pctx := &build.PipelineBuild{
+		Build: &build.Build{
+			Configuration: *cfg,
+		},
+		Package: &cfg.Package,
+	}
+	return build.MutateWith(pctx, map[string]string{})
+}
+
+// download gets uri and fails unless the response is a 200.  The caller must close the response body.
+func download(ctx context.Context, client *http.Client, uri string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", uri, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("download failed for %s, status code: %d", uri, resp.StatusCode)
+	}
+	return resp, nil
+}
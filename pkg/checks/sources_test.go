@@ -0,0 +1,135 @@
+package checks
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"chainguard.dev/melange/pkg/config"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSources_verifyFetchDigest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("hello\n"))
+	}))
+	defer server.Close()
+
+	o := SourcesOptions{Client: server.Client(), Logger: log.New(io.Discard, "", 0)}
+	m := map[string]string{"${{package.version}}": "1.0.0"}
+
+	tests := []struct {
+		name    string
+		with    map[string]string
+		wantErr string
+	}{
+		{
+			name: "sha256 matches",
+			with: map[string]string{
+				"uri":             server.URL + "/hello-${{package.version}}.tar.gz",
+				"expected-sha256": "5891b5b522d5df086d0ff0b110fbd9d21bb4fc7163af34d08286a2e846f6be03",
+			},
+		},
+		{
+			name: "sha512 matches",
+			with: map[string]string{
+				"uri":             server.URL + "/hello-${{package.version}}.tar.gz",
+				"expected-sha512": "e7c22b994c59d9cf2b48e549b1e24666636045930d3da7c1acb299d1c3b7f931f94aae41edda2c2b207a36e10f8bcb8d45223e54878f5b316e7ce3b6bc019629",
+			},
+		},
+		{
+			name: "sha256 mismatch",
+			with: map[string]string{
+				"uri":             server.URL + "/hello-${{package.version}}.tar.gz",
+				"expected-sha256": "0000000000000000000000000000000000000000000000000000000000000000",
+			},
+			wantErr: "digest of " + server.URL + "/hello-1.0.0.tar.gz is 5891b5b522d5df086d0ff0b110fbd9d21bb4fc7163af34d08286a2e846f6be03 but 0000000000000000000000000000000000000000000000000000000000000000 is expected",
+		},
+		{
+			name:    "no digest",
+			with:    map[string]string{"uri": server.URL + "/hello.tar.gz"},
+			wantErr: "no expected-sha256 or expected-sha512 for " + server.URL + "/hello.tar.gz",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := o.verifyFetchDigest(context.Background(), &config.Pipeline{Uses: "fetch", With: tt.with}, m)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSources_verifyGitRef(t *testing.T) {
+	dir := t.TempDir()
+	r, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	w, err := r.Worktree()
+	require.NoError(t, err)
+
+	sig := &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()}
+	first, err := w.Commit("first", &git.CommitOptions{AllowEmptyCommits: true, Author: sig})
+	require.NoError(t, err)
+	_, err = r.CreateTag("v1.0.0", first, &git.CreateTagOptions{Message: "v1.0.0", Tagger: sig})
+	require.NoError(t, err)
+	second, err := w.Commit("second", &git.CommitOptions{AllowEmptyCommits: true, Author: sig})
+	require.NoError(t, err)
+	_, err = r.CreateTag("v1.1.0", second, nil)
+	require.NoError(t, err)
+
+	o := SourcesOptions{Logger: log.New(io.Discard, "", 0)}
+	m := map[string]string{"${{package.version}}": "1.0.0", "${{vars.repository}}": dir}
+
+	tests := []struct {
+		name    string
+		with    map[string]string
+		wantErr string
+	}{
+		{
+			name: "annotated tag matches",
+			with: map[string]string{"repository": dir, "tag": "v${{package.version}}", "expected-commit": first.String()},
+		},
+		{
+			name: "lightweight tag matches",
+			with: map[string]string{"repository": dir, "tag": "v1.1.0", "expected-commit": second.String()},
+		},
+		{
+			name:    "tag moved",
+			with:    map[string]string{"repository": dir, "tag": "v1.1.0", "expected-commit": first.String()},
+			wantErr: "tag v1.1.0 of " + dir + " points at " + second.String() + " but expected-commit is " + first.String() + ", the tag may have moved",
+		},
+		{
+			name:    "tag missing",
+			with:    map[string]string{"repository": dir, "tag": "v2.0.0", "expected-commit": first.String()},
+			wantErr: "tag v2.0.0 not found in " + dir,
+		},
+		{
+			name: "templated repository",
+			with: map[string]string{"repository": "${{vars.repository}}", "tag": "v1.1.0", "expected-commit": second.String()},
+		},
+		{
+			name: "branch",
+			with: map[string]string{"repository": dir, "branch": "main", "expected-commit": first.String()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := o.verifyGitRef(context.Background(), &config.Pipeline{Uses: "git-checkout", With: tt.with}, m)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,6 @@
+int counter;
+
+/* bar is removed without changing the soname */
+int foo(void) { return 1; }
+int baz(void) { return 3; }
+int use(void) { return counter; }
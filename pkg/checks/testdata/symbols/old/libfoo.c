@@ -0,0 +1,5 @@
+int counter;
+
+int foo(void) { return 1; }
+int bar(void) { return 2; }
+int use(void) { return counter; }
@@ -7,6 +7,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/dprotaso/go-yit"
@@ -53,12 +54,7 @@ func (o CheckUpdateOptions) CheckUpdates(ctx context.Context, files []string) er
 
 	validateUpdateConfig(ctx, changedPackages, &checkErrors)
 
-	latestVersions, err := updateOpts.GetLatestVersions(ctx, o.Dir, changedPackages)
-	if err != nil {
-		addCheckError(&checkErrors, err)
-	}
-
-	handleErrorMessages(updateOpts, &checkErrors)
+	latestVersions := o.getLatestVersions(ctx, updateOpts, changedPackages, &checkErrors)
 
 	if o.OverrideVersion == "" {
 		o.checkForLatestVersions(ctx, latestVersions, &checkErrors)
@@ -155,12 +151,55 @@ func addCheckError(checkErrors *lint.EvalRuleErrors, err error) {
 	})
 }
 
+// getLatestVersions gets the latest versions of the packages according to their update config and reports any
+// package that got none back
+func (o CheckUpdateOptions) getLatestVersions(ctx context.Context, updateOpts *update.Options, packages []string, checkErrors *lint.EvalRuleErrors) map[string]update.NewVersionResults {
+	latestVersions, err := updateOpts.GetLatestVersions(ctx, o.Dir, packages)
+	if err != nil {
+		addCheckError(checkErrors, err)
+	}
+
+	handleErrorMessages(updateOpts, checkErrors)
+
+	// when the query fails the missing packages were never looked up, so they have nothing to report
+	if err == nil {
+		checkForMissingVersions(updateOpts, latestVersions, checkErrors)
+	}
+
+	return latestVersions
+}
+
 func handleErrorMessages(updateOpts *update.Options, checkErrors *lint.EvalRuleErrors) {
 	for _, message := range updateOpts.ErrorMessages {
 		addCheckError(checkErrors, errors.New(message))
 	}
 }
 
+// report GitHub monitored packages that got no versions back, this happens when the identifier, tag filter, strip
+// prefix or ignore patterns don't match any of the upstream releases or tags.  Release monitor reports this itself.
+func checkForMissingVersions(updateOpts *update.Options, latestVersions map[string]update.NewVersionResults, checkErrors *lint.EvalRuleErrors) {
+	names := make([]string, 0, len(updateOpts.PackageConfigs))
+	for name := range updateOpts.PackageConfigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		gh := updateOpts.PackageConfigs[name].Config.Update.GitHubMonitor
+		if gh == nil {
+			continue
+		}
+		if _, ok := latestVersions[name]; ok {
+			continue
+		}
+		// already reported by handleErrorMessages
+		if _, ok := updateOpts.ErrorMessages[name]; ok {
+			continue
+		}
+		addCheckError(checkErrors, fmt.Errorf("package %s: no versions found for github identifier %s, check the tag filter, strip prefix and ignore patterns match the upstream releases", name, gh.Identifier))
+	}
+}
+
 // check if the current package.version is the latest according to the update config
 func (o CheckUpdateOptions) checkForLatestVersions(ctx context.Context, latestVersions map[string]update.NewVersionResults, checkErrors *lint.EvalRuleErrors) {
 	for k, v := range latestVersions {
@@ -183,6 +222,11 @@ func (o CheckUpdateOptions) checkForLatestVersions(ctx context.Context, latestVe
 		if currentVersion.LessThan(latestVersion) {
 			addCheckError(checkErrors, fmt.Errorf("package %s: update found newer version %s compared with package.version %s in melange config", k, v.Version, c.Package.Version))
 		}
+		// the update config may be following the wrong tags, but the upstream services often lag a manual version
+		// bump so this is only a warning
+		if latestVersion.LessThan(currentVersion) {
+			o.Logger.Printf("warning: package %s: update found version %s which is older than package.version %s in melange config, check the update config follows the right releases and version transforms", k, v.Version, c.Package.Version)
+		}
 	}
 }
 
@@ -3,6 +3,7 @@ package checks
 import (
 	"bytes"
 	"context"
+	"io"
 	"log"
 	"net/http"
 	"net/http/httptest"
@@ -11,6 +12,7 @@ import (
 	"testing"
 	"time"
 
+	"chainguard.dev/melange/pkg/config"
 	"github.com/go-git/go-billy/v5/osfs"
 	"github.com/go-git/go-billy/v5/util"
 	"github.com/go-git/go-git/v5"
@@ -20,6 +22,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/wolfi-dev/wolfictl/pkg/lint"
+	"github.com/wolfi-dev/wolfictl/pkg/melange"
 
 	"github.com/wolfi-dev/wolfictl/pkg/update"
 )
@@ -172,3 +175,92 @@ func TestUpdateKeyExists(t *testing.T) {
 	validateUpdateConfig(ctx, []string{fileNoContainsUpdate}, &checkErrors)
 	assert.NotEmpty(t, checkErrors)
 }
+
+func TestCheckForLatestVersions(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "cheese.yaml"), []byte("package:\n  name: cheese\n  version: 1.2.0\n"), os.ModePerm)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		version string
+		wantErr string
+		wantLog string
+	}{
+		{name: "latest", version: "1.2.0"},
+		{name: "newer", version: "1.3.0", wantErr: "package cheese: update found newer version 1.3.0"},
+		// the upstream services can lag a manual bump, so an older version is only a warning
+		{name: "older", version: "1.1.0", wantLog: "warning: package cheese: update found version 1.1.0 which is older than package.version 1.2.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			o := CheckUpdateOptions{Dir: dir, Logger: log.New(&logs, "", 0)}
+			checkErrors := make(lint.EvalRuleErrors, 0)
+			o.checkForLatestVersions(ctx, map[string]update.NewVersionResults{"cheese": {Version: tt.version}}, &checkErrors)
+			if tt.wantLog != "" {
+				assert.Contains(t, logs.String(), tt.wantLog)
+			}
+			if tt.wantErr == "" {
+				assert.Empty(t, checkErrors)
+				return
+			}
+			assert.Len(t, checkErrors, 1)
+			assert.ErrorContains(t, checkErrors.WrapErrors(), tt.wantErr)
+		})
+	}
+}
+
+func TestCheckForMissingVersions(t *testing.T) {
+	packageConfig := func(name string, u config.Update) *melange.Packages {
+		return &melange.Packages{Config: config.Configuration{Package: config.Package{Name: name}, Update: u}}
+	}
+	updateOpts := &update.Options{
+		PackageConfigs: map[string]*melange.Packages{
+			"found":          packageConfig("found", config.Update{GitHubMonitor: &config.GitHubMonitor{Identifier: "foo/found"}}),
+			"missing":        packageConfig("missing", config.Update{GitHubMonitor: &config.GitHubMonitor{Identifier: "foo/missing"}}),
+			"errored":        packageConfig("errored", config.Update{GitHubMonitor: &config.GitHubMonitor{Identifier: "foo/errored"}}),
+			"releasemonitor": packageConfig("releasemonitor", config.Update{ReleaseMonitor: &config.ReleaseMonitor{Identifier: 1}}),
+		},
+		ErrorMessages: map[string]string{"errored": "failed"},
+	}
+	checkErrors := make(lint.EvalRuleErrors, 0)
+
+	checkForMissingVersions(updateOpts, map[string]update.NewVersionResults{"found": {Version: "1.0.0"}}, &checkErrors)
+
+	assert.Len(t, checkErrors, 1)
+	assert.ErrorContains(t, checkErrors.WrapErrors(), "package missing: no versions found for github identifier foo/missing")
+}
+
+func TestCheckUpdates_getLatestVersionsFails(t *testing.T) {
+	// without a token the GitHub query fails before any request is sent
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GITHUB_TOKENS", "")
+
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "cheese.yaml"), []byte(`package:
+  name: cheese
+  version: 1.0.0
+  epoch: 0
+update:
+  enabled: true
+  github:
+    identifier: wine/cheese
+`), 0o600)
+	assert.NoError(t, err)
+
+	updateOpts := update.New(context.Background())
+	updateOpts.GithubReleaseQuery = true
+	updateOpts.ErrorMessages = make(map[string]string)
+	updateOpts.Logger = log.New(io.Discard, "", 0)
+	checkErrors := make(lint.EvalRuleErrors, 0)
+
+	o := CheckUpdateOptions{Dir: dir, Logger: log.New(io.Discard, "", 0)}
+	o.getLatestVersions(context.Background(), &updateOpts, []string{"cheese"}, &checkErrors)
+
+	// only the query failure is reported, not a missing version for every GitHub package
+	assert.Len(t, checkErrors, 1)
+	assert.ErrorContains(t, checkErrors.WrapErrors(), "failed getting github releases")
+	assert.NotContains(t, checkErrors.WrapErrors().Error(), "no versions found")
+}
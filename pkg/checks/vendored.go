@@ -0,0 +1,315 @@
+package checks
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"chainguard.dev/melange/pkg/util"
+	"github.com/ulikunitz/xz"
+	"github.com/wolfi-dev/wolfictl/pkg/lint"
+	"github.com/wolfi-dev/wolfictl/pkg/melange"
+)
+
+// Kinds of vendored code found in a source archive.
+const (
+	VendoredGoModule  = "go-module"
+	VendoredNPM       = "npm"
+	VendoredArchive   = "archive"
+	VendoredDirectory = "directory"
+)
+
+// bundledArchiveSuffixes are the extensions of archives that are reported when checked in to a source tree.
+var bundledArchiveSuffixes = []string{".tar.gz", ".tgz", ".tar.xz", ".txz", ".tar.bz2", ".zip", ".jar", ".whl", ".gem", ".crate"}
+
+// archiveVersion matches the version at the end of an archive name, e.g. zlib-1.3.1
+var archiveVersion = regexp.MustCompile(`^(.+?)[-_]v?(\d+(?:\.\d+)+[a-z0-9.]*)$`)
+
+// Vendored is a copy of third party code bundled in a source archive.
+type Vendored struct {
+	Kind    string
+	Name    string
+	Version string
+	// Path is where the code was found in the archive
+	Path string
+}
+
+type VendoredOptions struct {
+	Client       *http.Client
+	Logger       *log.Logger
+	Dir          string
+	PackageNames []string
+}
+
+func NewVendored() *VendoredOptions {
+	o := &VendoredOptions{
+		Client: http.DefaultClient,
+		Logger: log.New(log.Writer(), "wolfictl check vendored: ", log.LstdFlags|log.Lmsgprefix),
+	}
+
+	return o
+}
+
+/*
+CheckVendored downloads the sources declared by the fetch pipelines of each package and reports the third party code
+bundled in them: Go vendor directories, checked in node_modules, third_party directories and archives.  Bundled copies
+are not visible to SBOM based scanning so unpatched vulnerabilities in them are easily missed.
+*/
+func (o *VendoredOptions) CheckVendored(ctx context.Context) error {
+	packages, err := melange.ReadPackageConfigs(ctx, o.PackageNames, o.Dir)
+	if err != nil {
+		return fmt.Errorf("failed to read package configs: %w", err)
+	}
+
+	names := make([]string, 0, len(packages))
+	for name := range packages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	checkErrors := make(lint.EvalRuleErrors, 0)
+	for _, name := range names {
+		cfg := packages[name].Config
+		mutations, err := pipelineMutations(&cfg)
+		if err != nil {
+			return err
+		}
+
+		for i := range cfg.Pipeline {
+			p := &cfg.Pipeline[i]
+			if p.Uses != "fetch" {
+				continue
+			}
+			uri, err := util.MutateStringFromMap(mutations, p.With["uri"])
+			if err != nil {
+				addCheckError(&checkErrors, fmt.Errorf("package %s: %w", name, err))
+				continue
+			}
+
+			vendored, err := o.findVendoredAt(ctx, uri)
+			if errors.Is(err, errUnsupportedArchive) {
+				o.Logger.Printf("package %s: skipping %s, it is not a compressed tarball", name, uri)
+				continue
+			}
+			if err != nil {
+				addCheckError(&checkErrors, fmt.Errorf("package %s: %w", name, err))
+				continue
+			}
+			if len(vendored) == 0 {
+				o.Logger.Printf("package %s: no vendored code found in %s", name, uri)
+				continue
+			}
+			for _, v := range vendored {
+				if v.Version == "" {
+					o.Logger.Printf("package %s: %s %s in %s", name, v.Kind, v.Name, v.Path)
+				} else {
+					o.Logger.Printf("package %s: %s %s %s in %s", name, v.Kind, v.Name, v.Version, v.Path)
+				}
+			}
+		}
+	}
+
+	return checkErrors.WrapErrors()
+}
+
+func (o *VendoredOptions) findVendoredAt(ctx context.Context, uri string) ([]Vendored, error) {
+	o.Logger.Printf("downloading %s", uri)
+
+	resp, err := download(ctx, o.Client, uri)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	vendored, err := FindVendored(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect %s: %w", uri, err)
+	}
+	return vendored, nil
+}
+
+// FindVendored reads a gzip, bzip2 or xz compressed tarball and returns the vendored code in it, sorted by path.
+func FindVendored(r io.Reader) ([]Vendored, error) {
+	dr, err := decompress(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var vendored []Vendored
+	// directories below vendor and third_party, the vendor ones are dropped if they turn out to be Go modules
+	dirs := map[string]bool{}
+	goVendorDirs := map[string]bool{}
+
+	tr := tar.NewReader(dr)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tar header: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := strings.TrimPrefix(path.Clean(hdr.Name), "./")
+		parts := strings.Split(name, "/")
+		base := parts[len(parts)-1]
+
+		switch {
+		case base == "modules.txt" && len(parts) > 1 && parts[len(parts)-2] == "vendor":
+			dir := path.Dir(name)
+			goVendorDirs[dir] = true
+			modules, err := parseModulesTxt(tr, dir)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+			}
+			vendored = append(vendored, modules...)
+			continue
+		case base == "package.json" && isNodeModule(parts):
+			v, err := parsePackageJSON(tr, path.Dir(name))
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+			}
+			vendored = append(vendored, v)
+			continue
+		case isBundledArchive(base):
+			v := Vendored{Kind: VendoredArchive, Name: base, Path: name}
+			stem := trimArchiveSuffix(base)
+			if m := archiveVersion.FindStringSubmatch(stem); m != nil {
+				v.Name, v.Version = m[1], strings.TrimSuffix(m[2], ".")
+			}
+			vendored = append(vendored, v)
+		}
+
+		// the last component is the file itself, so only directories below vendor and third_party are recorded
+		for i := 0; i < len(parts)-2; i++ {
+			if parts[i] == "vendor" || parts[i] == "third_party" {
+				dirs[strings.Join(parts[:i+2], "/")] = true
+			}
+		}
+	}
+
+	for dir := range dirs {
+		if goVendorDirs[path.Dir(dir)] || isInNodeModules(dir) {
+			continue
+		}
+		vendored = append(vendored, Vendored{Kind: VendoredDirectory, Name: path.Base(dir), Path: dir})
+	}
+
+	sort.Slice(vendored, func(i, j int) bool {
+		if vendored[i].Path != vendored[j].Path {
+			return vendored[i].Path < vendored[j].Path
+		}
+		return vendored[i].Name < vendored[j].Name
+	})
+
+	return vendored, nil
+}
+
+// errUnsupportedArchive is returned for sources that are not a compressed tarball, such as zip files or single
+// files, which can't be inspected.
+var errUnsupportedArchive = errors.New("unsupported archive, expected a gzip, bzip2 or xz compressed tarball")
+
+// decompress detects the compression of r from its magic bytes.
+func decompress(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(6)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+
+	switch {
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		return gzip.NewReader(br)
+	case bytes.HasPrefix(magic, []byte("BZh")):
+		return bzip2.NewReader(br), nil
+	case bytes.HasPrefix(magic, []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}):
+		return xz.NewReader(br)
+	default:
+		return nil, errUnsupportedArchive
+	}
+}
+
+// parseModulesTxt returns the modules listed in a Go vendor/modules.txt.  Replaced modules are reported with the
+// path and version of their replacement.
+func parseModulesTxt(r io.Reader, dir string) ([]Vendored, error) {
+	var modules []Vendored
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		// module lines look like "# path version" or "# path [version] => replacement [version]"
+		if !strings.HasPrefix(line, "# ") {
+			continue
+		}
+		fields := strings.Fields(strings.TrimPrefix(line, "# "))
+		if i := slices.Index(fields, "=>"); i >= 0 {
+			fields = fields[i+1:]
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		m := Vendored{Kind: VendoredGoModule, Name: fields[0], Path: dir}
+		if len(fields) > 1 {
+			m.Version = fields[1]
+		}
+		modules = append(modules, m)
+	}
+	return modules, scanner.Err()
+}
+
+func parsePackageJSON(r io.Reader, dir string) (Vendored, error) {
+	var pkg struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+	if err := json.NewDecoder(r).Decode(&pkg); err != nil {
+		return Vendored{}, err
+	}
+	if pkg.Name == "" {
+		pkg.Name = path.Base(dir)
+	}
+	return Vendored{Kind: VendoredNPM, Name: pkg.Name, Version: pkg.Version, Path: dir}, nil
+}
+
+// isNodeModule reports whether parts is the path of the package.json at the root of a package in node_modules,
+// either node_modules/name/package.json or node_modules/@scope/name/package.json.
+func isNodeModule(parts []string) bool {
+	n := len(parts)
+	if n >= 3 && parts[n-3] == "node_modules" && !strings.HasPrefix(parts[n-2], "@") {
+		return true
+	}
+	return n >= 4 && parts[n-4] == "node_modules" && strings.HasPrefix(parts[n-3], "@")
+}
+
+func isInNodeModules(dir string) bool {
+	return slices.Contains(strings.Split(dir, "/"), "node_modules")
+}
+
+func isBundledArchive(name string) bool {
+	return trimArchiveSuffix(name) != name
+}
+
+func trimArchiveSuffix(name string) string {
+	for _, s := range bundledArchiveSuffixes {
+		if strings.HasSuffix(name, s) {
+			return strings.TrimSuffix(name, s)
+		}
+	}
+	return name
+}
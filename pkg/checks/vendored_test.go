@@ -0,0 +1,156 @@
+package checks
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ulikunitz/xz"
+)
+
+func testTarball(t *testing.T, w io.WriteCloser, files map[string]string) {
+	t.Helper()
+
+	tw := tar.NewWriter(w)
+	for name, contents := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(contents)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(contents))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, w.Close())
+}
+
+func TestFindVendored(t *testing.T) {
+	files := map[string]string{
+		"foo-1.0.0/main.go": "package main",
+		"foo-1.0.0/vendor/modules.txt": `# github.com/pkg/errors v0.9.1
+## explicit
+github.com/pkg/errors
+# golang.org/x/net v0.17.0 => golang.org/x/net v0.23.0
+## explicit; go 1.18
+golang.org/x/net/http2
+`,
+		"foo-1.0.0/vendor/github.com/pkg/errors/errors.go":                     "package errors",
+		"foo-1.0.0/web/node_modules/lodash/package.json":                       `{"name": "lodash", "version": "4.17.20"}`,
+		"foo-1.0.0/web/node_modules/lodash/vendor/underscore.js":               "",
+		"foo-1.0.0/web/node_modules/@babel/core/package.json":                  `{"name": "@babel/core", "version": "7.23.0"}`,
+		"foo-1.0.0/web/node_modules/@babel/core/lib/index.js":                  "",
+		"foo-1.0.0/web/node_modules/@babel/core/node_modules/semver/README.md": "",
+		"foo-1.0.0/third_party/zlib/zlib.h":                                    "",
+		"foo-1.0.0/deps/openssl-3.1.4.tar.gz":                                  "",
+		"foo-1.0.0/lib/bundled.jar":                                            "",
+		"foo-1.0.0/README.md":                                                  "",
+	}
+	want := []Vendored{
+		{Kind: VendoredArchive, Name: "openssl", Version: "3.1.4", Path: "foo-1.0.0/deps/openssl-3.1.4.tar.gz"},
+		{Kind: VendoredArchive, Name: "bundled.jar", Path: "foo-1.0.0/lib/bundled.jar"},
+		{Kind: VendoredDirectory, Name: "zlib", Path: "foo-1.0.0/third_party/zlib"},
+		{Kind: VendoredGoModule, Name: "github.com/pkg/errors", Version: "v0.9.1", Path: "foo-1.0.0/vendor"},
+		{Kind: VendoredGoModule, Name: "golang.org/x/net", Version: "v0.23.0", Path: "foo-1.0.0/vendor"},
+		{Kind: VendoredNPM, Name: "@babel/core", Version: "7.23.0", Path: "foo-1.0.0/web/node_modules/@babel/core"},
+		{Kind: VendoredNPM, Name: "lodash", Version: "4.17.20", Path: "foo-1.0.0/web/node_modules/lodash"},
+	}
+
+	t.Run("gzip", func(t *testing.T) {
+		var buf bytes.Buffer
+		testTarball(t, gzip.NewWriter(&buf), files)
+
+		got, err := FindVendored(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("xz", func(t *testing.T) {
+		var buf bytes.Buffer
+		xw, err := xz.NewWriter(&buf)
+		require.NoError(t, err)
+		testTarball(t, xw, files)
+
+		got, err := FindVendored(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("not a tarball", func(t *testing.T) {
+		_, err := FindVendored(bytes.NewBufferString("hello"))
+		assert.EqualError(t, err, "unsupported archive, expected a gzip, bzip2 or xz compressed tarball")
+	})
+}
+
+func TestVendored_findVendoredAt(t *testing.T) {
+	var buf bytes.Buffer
+	testTarball(t, gzip.NewWriter(&buf), map[string]string{"bar-2.0/third_party/abseil/README.md": ""})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/bar-2.0.tar.gz" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(buf.Bytes())
+	}))
+	defer server.Close()
+
+	o := VendoredOptions{Client: server.Client(), Logger: log.New(io.Discard, "", 0)}
+
+	got, err := o.findVendoredAt(context.Background(), server.URL+"/bar-2.0.tar.gz")
+	require.NoError(t, err)
+	assert.Equal(t, []Vendored{{Kind: VendoredDirectory, Name: "abseil", Path: "bar-2.0/third_party/abseil"}}, got)
+
+	_, err = o.findVendoredAt(context.Background(), server.URL+"/missing.tar.gz")
+	assert.EqualError(t, err, "download failed for "+server.URL+"/missing.tar.gz, status code: 404")
+}
+
+func TestVendored_CheckVendored(t *testing.T) {
+	var buf bytes.Buffer
+	testTarball(t, gzip.NewWriter(&buf), map[string]string{"cheese-6.8/third_party/abseil/README.md": ""})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/cheese-6.8.tar.gz":
+			_, _ = w.Write(buf.Bytes())
+		case "/cheese-6.8.zip":
+			_, _ = w.Write([]byte("PK\x03\x04"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	cfg := fmt.Sprintf(`package:
+  name: cheese
+  version: 6.8
+  epoch: 0
+pipeline:
+  - uses: fetch
+    with:
+      uri: %[1]s/cheese-${{package.version}}.zip
+  - uses: fetch
+    with:
+      uri: %[1]s/cheese-${{package.version}}.tar.gz
+`, server.URL)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cheese.yaml"), []byte(cfg), 0o600))
+
+	var logs bytes.Buffer
+	o := VendoredOptions{Client: server.Client(), Logger: log.New(&logs, "", 0), Dir: dir}
+
+	// the zip is skipped and the tarball after it is still inspected
+	require.NoError(t, o.CheckVendored(context.Background()))
+	assert.Contains(t, logs.String(), "package cheese: skipping "+server.URL+"/cheese-6.8.zip, it is not a compressed tarball")
+	assert.Contains(t, logs.String(), "package cheese: directory abseil in cheese-6.8/third_party/abseil")
+
+	_, err := o.findVendoredAt(context.Background(), server.URL+"/cheese-6.8.zip")
+	assert.ErrorIs(t, err, errUnsupportedArchive)
+}
@@ -89,6 +89,7 @@ func cmdApk() *cobra.Command {
 	}
 	cmd.Flags().StringVar(&arch, "arch", "x86_64", "arch of package to get")
 	cmd.Flags().StringVar(&repo, "repo", "wolfi", "repo to get packages from")
+	cmd.AddCommand(cmdApkDiff())
 	return cmd
 }
 
@@ -115,6 +116,46 @@ func cmdIndex() *cobra.Command {
 	}
 	cmd.Flags().StringVar(&arch, "arch", "x86_64", "arch of package to get")
 	cmd.Flags().StringVar(&repo, "repo", "wolfi", "repo to get packages from")
+	cmd.AddCommand(cmdIndexDiff())
+	return cmd
+}
+
+func cmdIndexDiff() *cobra.Command {
+	var output string
+	cmd := &cobra.Command{
+		Use:   "diff <old> <new>",
+		Short: "Show packages added, removed, upgraded or downgraded between two APKINDEX files",
+		Long: `Show packages added, removed, upgraded or downgraded between two APKINDEX files
+
+Each argument is a local path or URL of an APKINDEX.tar.gz. Only the latest
+version of each package in either index is compared. Packages whose version
+changed but can't be parsed, so can't be ordered, are listed as changed.`,
+		Example: `  wolfictl index diff old/APKINDEX.tar.gz https://packages.wolfi.dev/os/x86_64/APKINDEX.tar.gz --output markdown`,
+		Args:    cobra.ExactArgs(2),
+		RunE: func(_ *cobra.Command, args []string) error {
+			oldIdx, err := index.Open(args[0])
+			if err != nil {
+				return err
+			}
+			newIdx, err := index.Open(args[1])
+			if err != nil {
+				return err
+			}
+
+			diff := index.DiffIndexes(oldIdx, newIdx)
+			switch output {
+			case "json":
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(diff)
+			case "markdown":
+				return diff.WriteMarkdown(os.Stdout)
+			default:
+				return fmt.Errorf("unsupported output format %q, must be one of json or markdown", output)
+			}
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "json", "output format, one of json or markdown")
 	return cmd
 }
 
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/apk"
+)
+
+func cmdApkDiff() *cobra.Command {
+	var output string
+	cmd := &cobra.Command{
+		Use:   "diff <old.apk> <new.apk>",
+		Short: "Show file and metadata differences between two APKs",
+		Long: `Show file and metadata differences between two APKs
+
+Reports files that were added, removed or changed (mode, size or contents),
+the change in total installed size, and dependencies and provides that were
+added or removed in the .PKGINFO.`,
+		Example: `  wolfictl apk diff packages/x86_64/foo-1.0-r0.apk packages/x86_64/foo-1.1-r0.apk`,
+		Args:    cobra.ExactArgs(2),
+		RunE: func(_ *cobra.Command, args []string) error {
+			oldFile, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer oldFile.Close()
+			newFile, err := os.Open(args[1])
+			if err != nil {
+				return err
+			}
+			defer newFile.Close()
+
+			diff, err := apk.DiffAPKs(oldFile, newFile)
+			if err != nil {
+				return err
+			}
+
+			switch output {
+			case "json":
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(diff)
+			case "text":
+				return diff.WriteText(os.Stdout)
+			default:
+				return fmt.Errorf("unsupported output format %q, must be one of text or json", output)
+			}
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "output format, one of text or json")
+	return cmd
+}
@@ -10,14 +10,17 @@ import (
 
 	"chainguard.dev/melange/pkg/config"
 	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/dag"
 )
 
 const epochPattern = `epoch: %d`
 
 type bumpOptions struct {
-	repoDir string
-	epoch   bool
-	dryRun  bool
+	repoDir     string
+	pipelineDir string
+	epoch       bool
+	dryRun      bool
+	reverseDeps bool
 }
 
 // this feels very hacky but the Makefile is going away with help from Dag so plan to delete this func soon
@@ -77,6 +80,15 @@ repository. To look for files in another location use the --repo flag.
 You can use --dry-run to see which versions will be bumped without
 modifying anything in the filesystem.
 
+When a library changes, the packages built against it need to be rebuilt.
+With --reverse-deps the arguments are package names, and instead of the
+named packages, every package in the repository that depends on them
+(directly or transitively, through build-time or runtime dependencies)
+is bumped:
+
+    wolfictl bump --reverse-deps openssl
+    wolfictl bump --reverse-deps --dry-run zlib
+
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
@@ -84,24 +96,32 @@ modifying anything in the filesystem.
 				cmd.Help() //nolint:errcheck
 				return fmt.Errorf("not enough arguments")
 			}
-			files := []string{}
-			for _, fname := range args {
-				_, err := os.Stat(filepath.Join(opts.repoDir, fname+".yaml"))
-				if err == nil {
-					files = append(files, filepath.Join(opts.repoDir, fname+".yaml"))
-					continue
-				}
-
-				if !os.IsNotExist(err) {
-					return fmt.Errorf("while checking config path %s: %w", fname, err)
+			var files []string
+			if opts.reverseDeps {
+				var err error
+				files, err = reverseDepFiles(ctx, opts, args)
+				if err != nil {
+					return err
 				}
-
-				m, err := filepath.Glob(filepath.Join(opts.repoDir, fname))
-				if err == nil {
-					files = append(files, m...)
-					continue
+			} else {
+				for _, fname := range args {
+					_, err := os.Stat(filepath.Join(opts.repoDir, fname+".yaml"))
+					if err == nil {
+						files = append(files, filepath.Join(opts.repoDir, fname+".yaml"))
+						continue
+					}
+
+					if !os.IsNotExist(err) {
+						return fmt.Errorf("while checking config path %s: %w", fname, err)
+					}
+
+					m, err := filepath.Glob(filepath.Join(opts.repoDir, fname))
+					if err == nil {
+						files = append(files, m...)
+						continue
+					}
+					return fmt.Errorf("unable to find config files from: %s", fname)
 				}
-				return fmt.Errorf("unable to find config files from: %s", fname)
 			}
 
 			if opts.dryRun {
@@ -120,10 +140,42 @@ modifying anything in the filesystem.
 	cmd.Flags().BoolVar(&opts.epoch, "epoch", true, "bump the package epoch")
 	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "don't change anything, just print what would be done")
 	cmd.Flags().StringVar(&opts.repoDir, "repo", ".", "path to the wolfi/os repository")
+	cmd.Flags().BoolVar(&opts.reverseDeps, "reverse-deps", false, "bump the packages that depend on the named packages instead of the packages themselves")
+	cmd.Flags().StringVar(&opts.pipelineDir, "pipeline-dir", "", "directory used to extend defined built-in pipelines")
 
 	return cmd
 }
 
+// reverseDepFiles returns the config files of every package in the repository
+// that depends on one of the named packages.
+func reverseDepFiles(ctx context.Context, opts bumpOptions, names []string) ([]string, error) {
+	pipelineDir := opts.pipelineDir
+	if pipelineDir == "" {
+		pipelineDir = filepath.Join(opts.repoDir, "pipelines")
+	}
+
+	pkgs, err := dag.NewPackages(ctx, os.DirFS(opts.repoDir), opts.repoDir, pipelineDir)
+	if err != nil {
+		return nil, fmt.Errorf("reading package configs: %w", err)
+	}
+	g, err := dag.NewGraph(ctx, pkgs, dag.WithAllowUnresolved())
+	if err != nil {
+		return nil, fmt.Errorf("building graph: %w", err)
+	}
+
+	dependents, err := g.Dependents(names...)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(dependents))
+	for _, c := range dependents {
+		files = append(files, c.Path)
+	}
+	fmt.Fprintf(os.Stderr, "found %d packages depending on %s\n", len(files), strings.Join(names, ", "))
+	return files, nil
+}
+
 func bumpEpoch(ctx context.Context, opts bumpOptions, path string) error {
 	cfg, err := config.ParseConfiguration(ctx, path)
 	if err != nil {
@@ -16,6 +16,9 @@ func cmdCheck() *cobra.Command {
 		Diff(),
 		CheckUpdate(),
 		SoName(),
+		CheckSources(),
+		CheckVendored(),
+		CheckGoVersion(),
 	)
 	return cmd
 }
@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/checks"
+)
+
+func CheckGoVersion() *cobra.Command {
+	o := checks.NewGoVersion()
+	cmd := &cobra.Command{
+		Use:               "go-version [package...]",
+		DisableAutoGenTag: true,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+		Short:             "Check the go directives of Go packages are satisfied by the distro Go toolchains",
+		Long: `Check the go directives of Go packages are satisfied by the distro Go toolchains
+
+Reads every go.mod in the sources of packages built with Go, from their fetch
+tarballs and git-checkout repositories, and compares the go directive with the
+version of the Go toolchain package they build with: a version stream such as
+go-1.21 listed in the environment, or the latest go from the APKINDEX.
+Packages pinned to an older stream than the latest go are also logged.
+Packages default to every melange config in the directory.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.PackageNames = args
+			return o.CheckGoVersion(cmd.Context())
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.Dir, "directory", "d", ".", "directory containing melange configs")
+	cmd.Flags().StringVarP(&o.ApkIndexURL, "apk-index-url", "", "https://packages.wolfi.dev/os/x86_64/APKINDEX.tar.gz", "apk-index-url used to get the available Go toolchains.  Defaults to wolfi")
+
+	return cmd
+}
@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/checks"
+)
+
+func CheckSources() *cobra.Command {
+	o := checks.NewSources()
+	cmd := &cobra.Command{
+		Use:               "sources [package...]",
+		DisableAutoGenTag: true,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+		Short:             "Check fetched sources still match their pinned checksums and commits",
+		Long: `Check fetched sources still match their pinned checksums and commits
+
+Downloads the uri of every fetch pipeline and verifies its expected-sha256 or
+expected-sha512, and lists the tags of every git-checkout repository to verify
+the tag still points at the expected-commit. Packages default to every melange
+config in the directory.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.PackageNames = args
+			return o.CheckSources(cmd.Context())
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.Dir, "directory", "d", ".", "directory containing melange configs")
+
+	return cmd
+}
@@ -15,10 +15,25 @@ func CheckUpdate() *cobra.Command {
 
 	cmd := &cobra.Command{
 		Use:               "update [config[.yaml]...]",
+		Aliases:           []string{"update-config"},
 		DisableAutoGenTag: true,
 		SilenceUsage:      true,
 		SilenceErrors:     true,
 		Short:             "Check Wolfi update configs",
+		Long: `Check Wolfi update configs
+
+Queries release-monitoring.org or GitHub with the update config of each melange
+config and verifies the fetch and git-checkout pipelines work with the latest
+version. The check fails when:
+
+  - the update config is invalid or the service can't be queried
+  - a GitHub monitored package gets no versions back, usually because the
+    identifier, tag filter, strip prefix or ignore patterns don't match the
+    upstream releases
+  - a newer version than package.version is found
+
+A latest version older than package.version is only logged as a warning, as the
+services often lag a manual version bump.`,
 		RunE: func(cmd *cobra.Command, files []string) error {
 			return o.CheckUpdates(cmd.Context(), files)
 		},
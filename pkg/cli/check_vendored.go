@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/checks"
+)
+
+func CheckVendored() *cobra.Command {
+	o := checks.NewVendored()
+	cmd := &cobra.Command{
+		Use:               "vendored [package...]",
+		DisableAutoGenTag: true,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+		Short:             "Report third party code bundled in fetched sources",
+		Long: `Report third party code bundled in fetched sources
+
+Downloads the uri of every fetch pipeline and lists the vendored code found in
+the tarball: Go modules from vendor/modules.txt, packages in node_modules,
+directories below vendor and third_party, and checked in archives, along with
+their versions where known. Sources from git-checkout are not inspected.
+Packages default to every melange config in the directory.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.PackageNames = args
+			return o.CheckVendored(cmd.Context())
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.Dir, "directory", "d", ".", "directory containing melange configs")
+
+	return cmd
+}
@@ -3,6 +3,10 @@ package cli
 import (
 	"context"
 	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/wolfi-dev/wolfictl/pkg/lint"
@@ -12,6 +16,9 @@ type lintOptions struct {
 	args      []string
 	list      bool
 	skipRules []string
+	severity  []string
+	failOn    string
+	output    string
 }
 
 func cmdLint() *cobra.Command {
@@ -31,6 +38,9 @@ func cmdLint() *cobra.Command {
 	}
 	cmd.Flags().BoolVarP(&o.list, "list", "l", false, "prints the all of available rules and exits")
 	cmd.Flags().StringArrayVarP(&o.skipRules, "skip-rule", "", []string{}, "list of rules to skip")
+	cmd.Flags().StringArrayVar(&o.severity, "severity", []string{}, "override the severity of a rule, e.g. valid-copyright-header=warning")
+	cmd.Flags().StringVar(&o.failOn, "fail-on", "info", "lowest severity of a failed rule that fails the run, one of error, warning or info")
+	cmd.Flags().StringVarP(&o.output, "output", "o", "text", "output format, one of text, json or sarif")
 
 	cmd.AddCommand(cmdLintYam())
 
@@ -38,7 +48,15 @@ func cmdLint() *cobra.Command {
 }
 
 func (o lintOptions) LintCmd(ctx context.Context) error {
-	linter := lint.New(o.makeLintOptions()...)
+	opts, err := o.makeLintOptions()
+	if err != nil {
+		return err
+	}
+	failOn, err := lint.ParseSeverity(o.failOn)
+	if err != nil {
+		return fmt.Errorf("invalid --fail-on: %w", err)
+	}
+	linter := lint.New(opts...)
 
 	// If the list flag is set, print the list of available rules and exit.
 	if o.list {
@@ -51,21 +69,59 @@ func (o lintOptions) LintCmd(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	if result.HasErrors() {
-		linter.Print(ctx, result)
+
+	switch o.output {
+	case "json":
+		err = linter.PrintJSON(os.Stdout, result)
+	case "sarif":
+		err = linter.PrintSARIF(os.Stdout, result)
+	default:
+		if result.HasErrors() {
+			linter.Print(ctx, result)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if result.HasErrorsAtOrAbove(failOn) {
 		return errors.New("linting failed")
 	}
 	return nil
 }
 
-func (o lintOptions) makeLintOptions() []lint.Option {
+func (o lintOptions) makeLintOptions() ([]lint.Option, error) {
 	if len(o.args) == 0 {
 		// Lint the current directory by default.
 		o.args = []string{"."}
 	}
 
+	switch o.output {
+	case "text", "json", "sarif":
+	default:
+		return nil, fmt.Errorf("unsupported output format %q, must be one of text, json or sarif", o.output)
+	}
+
+	rules := lint.New().RuleNames()
+	severities := map[string]lint.Severity{}
+	for _, s := range o.severity {
+		name, level, ok := strings.Cut(s, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid --severity %q, must be in the form rule=severity", s)
+		}
+		if !slices.Contains(rules, name) {
+			return nil, fmt.Errorf("invalid --severity %q, unknown rule %q, see --list for the available rules", s, name)
+		}
+		sev, err := lint.ParseSeverity(level)
+		if err != nil {
+			return nil, err
+		}
+		severities[name] = sev
+	}
+
 	return []lint.Option{
 		lint.WithPath(o.args[0]),
 		lint.WithSkipRules(o.skipRules),
-	}
+		lint.WithSeverities(severities),
+	}, nil
 }
@@ -50,6 +50,7 @@ func cmdUpdate() *cobra.Command {
 
 	cmd.AddCommand(
 		Package(),
+		cmdUpdateRemediate(),
 	)
 
 	return cmd
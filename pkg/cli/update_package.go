@@ -2,14 +2,13 @@ package cli
 
 import (
 	"log"
-	"os"
+	"net/http"
 	"time"
 
 	"github.com/google/go-github/v58/github"
 	"github.com/spf13/cobra"
-	"github.com/wolfi-dev/wolfictl/pkg/http"
+	http2 "github.com/wolfi-dev/wolfictl/pkg/http"
 	"github.com/wolfi-dev/wolfictl/pkg/update"
-	"golang.org/x/oauth2"
 	"golang.org/x/time/rate"
 )
 
@@ -31,12 +30,8 @@ func Package() *cobra.Command {
 				}
 			}
 
-			ts := oauth2.StaticTokenSource(
-				&oauth2.Token{AccessToken: os.Getenv("GITHUB_TOKEN")},
-			)
-
-			ratelimit := &http.RLHTTPClient{
-				Client: oauth2.NewClient(cmd.Context(), ts),
+			ratelimit := &http2.RLHTTPClient{
+				Client: &http.Client{Transport: http2.NewGitHubTransport(http.DefaultTransport, http2.GitHubTokens(), o.Logger)},
 
 				// 1 request every (n) second(s) to avoid DOS'ing server. https://docs.github.com/en/rest/guides/best-practices-for-integrators?apiVersion=2022-11-28#dealing-with-secondary-rate-limits
 				Ratelimiter: rate.NewLimiter(rate.Every(3*time.Second), 1),
@@ -0,0 +1,204 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	melangebuild "chainguard.dev/melange/pkg/build"
+	"chainguard.dev/melange/pkg/config"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/wolfi-dev/wolfictl/pkg/melange"
+	"github.com/wolfi-dev/wolfictl/pkg/update/deps"
+	"github.com/wolfi-dev/wolfictl/pkg/yam"
+)
+
+type remediateOptions struct {
+	dir      string
+	osvFiles []string
+	dryRun   bool
+}
+
+func cmdUpdateRemediate() *cobra.Command {
+	o := remediateOptions{}
+	cmd := &cobra.Command{
+		Use:   "remediate --osv <file> [package...]",
+		Short: "Bumps vulnerable Go modules in melange go/bump pipelines",
+		Long: `Bumps vulnerable Go modules in melange go/bump pipelines
+
+The remediate subcommand reads OSV vulnerability records for Go modules and
+checks out the sources of each Go package in the directory. When a package's
+go.mod requires an affected module version, the lowest version that fixes
+every vulnerability is added to the package's go/bump pipeline (a go/bump step
+is added after git-checkout if the package doesn't have one) and the package
+epoch is bumped.
+
+Each --osv file may contain a single OSV record or a JSON array of records.
+Vulnerabilities without a fixed version are reported but not remediated.
+Use --dry-run to print the plan without modifying any files.
+`,
+		Example: `  wolfictl update remediate --osv GO-2024-2687.json
+  wolfictl update remediate --osv GO-2024-2687.json --dry-run crane`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(o.osvFiles) == 0 {
+				return fmt.Errorf("at least one --osv file is required")
+			}
+			return o.run(cmd.Context(), args)
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.dir, "dir", "d", ".", "directory containing melange configs")
+	cmd.Flags().StringSliceVar(&o.osvFiles, "osv", nil, "path to a file containing OSV vulnerability data")
+	cmd.Flags().BoolVar(&o.dryRun, "dry-run", false, "don't change anything, just print the remediation plan")
+
+	return cmd
+}
+
+func (o remediateOptions) run(ctx context.Context, packageNames []string) error {
+	vulns, err := readOSVFiles(o.osvFiles)
+	if err != nil {
+		return err
+	}
+
+	packages, err := melange.ReadPackageConfigs(ctx, packageNames, o.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read package configs: %w", err)
+	}
+
+	names := make([]string, 0, len(packages))
+	for name, pc := range packages {
+		if isGoPackage(&pc.Config) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		pc := packages[name]
+		fixes, err := o.remediatePackage(ctx, pc, vulns)
+		if err != nil {
+			// keep going so one broken package doesn't block remediation of the rest
+			fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+			continue
+		}
+
+		for _, fix := range fixes {
+			switch {
+			case fix.FixedVersion == "":
+				fmt.Printf("%s: %s@%s has no fix for %s\n", name, fix.Path, fix.CurrentVersion, strings.Join(fix.Unfixed, ", "))
+			case len(fix.Unfixed) > 0:
+				fmt.Printf("%s: %s@%s -> %s (%s), no fix for %s\n", name, fix.Path, fix.CurrentVersion, fix.FixedVersion, strings.Join(fix.Vulnerabilities, ", "), strings.Join(fix.Unfixed, ", "))
+			default:
+				fmt.Printf("%s: %s@%s -> %s (%s)\n", name, fix.Path, fix.CurrentVersion, fix.FixedVersion, strings.Join(fix.Vulnerabilities, ", "))
+			}
+		}
+	}
+
+	return nil
+}
+
+func (o remediateOptions) remediatePackage(ctx context.Context, pc *melange.Packages, vulns []models.Vulnerability) ([]deps.ModuleFix, error) {
+	configFile := filepath.Join(o.dir, pc.Filename)
+
+	yamlContent, err := os.ReadFile(configFile)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(yamlContent, &doc); err != nil {
+		return nil, fmt.Errorf("error unmarshalling YAML: %v", err)
+	}
+
+	cfg := pc.Config
+	pctx := &melangebuild.PipelineBuild{
+		Build: &melangebuild.Build{
+			Configuration: cfg,
+		},
+		Package: &cfg.Package,
+	}
+
+	// get a map of variable mutations we can substitute vars in URLs
+	mutations, err := melangebuild.MutateWith(pctx, map[string]string{})
+	if err != nil {
+		return nil, err
+	}
+
+	fixes, err := deps.RemediateGoBumpDeps(&doc, &cfg, vulns, mutations)
+	if err != nil {
+		return nil, err
+	}
+
+	fixable := false
+	for _, fix := range fixes {
+		if fix.FixedVersion != "" {
+			fixable = true
+		}
+	}
+	if !fixable || o.dryRun {
+		return fixes, nil
+	}
+
+	modifiedYAML, err := yaml.Marshal(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling YAML: %v", err)
+	}
+	if err := os.WriteFile(configFile, modifiedYAML, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write configuration file: %v", err)
+	}
+
+	if err := yam.FormatConfigurationFile(o.dir, pc.Filename); err != nil {
+		return nil, err
+	}
+
+	if err := bumpEpoch(ctx, bumpOptions{repoDir: o.dir, epoch: true}, configFile); err != nil {
+		return nil, err
+	}
+
+	return fixes, nil
+}
+
+// readOSVFiles reads OSV records from files holding either a single record or
+// a JSON array of records.
+func readOSVFiles(files []string) ([]models.Vulnerability, error) {
+	var vulns []models.Vulnerability
+	for _, f := range files {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("reading OSV data: %w", err)
+		}
+
+		var records []models.Vulnerability
+		if err := json.Unmarshal(b, &records); err != nil {
+			var record models.Vulnerability
+			if err := json.Unmarshal(b, &record); err != nil {
+				return nil, fmt.Errorf("parsing OSV data from %s: %w", f, err)
+			}
+			records = []models.Vulnerability{record}
+		}
+		vulns = append(vulns, records...)
+	}
+	return vulns, nil
+}
+
+// isGoPackage reports whether a melange config looks like it builds Go sources,
+// so we don't clone the sources of packages that can't be affected.
+func isGoPackage(cfg *config.Configuration) bool {
+	for i := range cfg.Pipeline {
+		if strings.HasPrefix(cfg.Pipeline[i].Uses, "go/") {
+			return true
+		}
+	}
+	for _, p := range cfg.Environment.Contents.Packages {
+		if p == "go" || strings.HasPrefix(p, "go-") {
+			return true
+		}
+	}
+	return false
+}
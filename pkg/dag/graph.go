@@ -777,6 +777,71 @@ func (g Graph) RequirementsOf(node string) []string {
 	return nil
 }
 
+// Dependents returns the configurations of the local origin packages that
+// depend, directly or transitively, on any of the given origin packages or
+// their subpackages. The given packages themselves are not included. The
+// result is sorted alphabetically by package name.
+func (g Graph) Dependents(names ...string) ([]*Configuration, error) {
+	predecessorMap, err := g.Graph.PredecessorMap()
+	if err != nil {
+		return nil, err
+	}
+
+	exclude := map[string]struct{}{}
+	var todo []string
+	for _, name := range names {
+		configs := g.packages.Config(name, true)
+		if len(configs) == 0 {
+			return nil, fmt.Errorf("unable to find package %q", name)
+		}
+		for _, c := range configs {
+			todo = append(todo, PackageHash(c))
+		}
+		exclude[name] = struct{}{}
+	}
+
+	seen := map[string]struct{}{}
+	dependents := map[string]*Configuration{}
+	for len(todo) > 0 {
+		key := todo[0]
+		todo = todo[1:]
+
+		for dependent := range predecessorMap[key] {
+			if _, ok := seen[dependent]; ok {
+				continue
+			}
+			seen[dependent] = struct{}{}
+			todo = append(todo, dependent)
+
+			vertex, err := g.Graph.Vertex(dependent)
+			if err != nil {
+				return nil, err
+			}
+			c, ok := vertex.(*Configuration)
+			if !ok {
+				continue
+			}
+			// subpackages share their origin's configuration
+			origin := c.Package.Name
+			if _, ok := exclude[origin]; ok {
+				continue
+			}
+			if cfg := g.packages.PkgConfig(origin); cfg != nil {
+				dependents[origin] = cfg
+			}
+		}
+	}
+
+	result := make([]*Configuration, 0, len(dependents))
+	for _, c := range dependents {
+		result = append(result, c)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Package.Name < result[j].Package.Name
+	})
+	return result, nil
+}
+
 // Packages returns a slice of the names of all origin packages, sorted alphabetically.
 func (g Graph) Packages() []string {
 	return g.packages.PackageNames()
@@ -295,3 +295,41 @@ func TestTargets(t *testing.T) {
 		assert.ElementsMatch(t, want, keys)
 	}
 }
+
+func TestDependents(t *testing.T) {
+	ctx := context.Background()
+	testDir := "testdata/subpackages"
+
+	pkgs, err := NewPackages(ctx, os.DirFS(testDir), testDir, "")
+	require.NoError(t, err)
+	graph, err := NewGraph(ctx, pkgs, WithAllowUnresolved())
+	require.NoError(t, err)
+
+	names := func(configs []*Configuration) []string {
+		var out []string
+		for _, c := range configs {
+			out = append(out, c.Package.Name)
+		}
+		return out
+	}
+
+	// two depends on one via the one-dev subpackage, and three depends on two
+	got, err := graph.Dependents("one")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"three", "two"}, names(got))
+
+	got, err = graph.Dependents("two")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"three"}, names(got))
+
+	got, err = graph.Dependents("three")
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	got, err = graph.Dependents("one", "two")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"three"}, names(got))
+
+	_, err = graph.Dependents("missing")
+	assert.Error(t, err)
+}
@@ -0,0 +1,226 @@
+package http
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultGitHubMaxWait is how long a request waits for a GitHub rate limit to reset before giving up.
+const DefaultGitHubMaxWait = 15 * time.Minute
+
+// GitHubTransport authenticates requests to the GitHub API with a pool of tokens.  It tracks the rate limit of each
+// token from the X-RateLimit response headers, stays on a token until it is rate limited then moves on to the next
+// and, when all of them are, waits for the earliest reset before retrying.  Writes, such as opening or closing pull
+// requests and issues, always use the first token so they are made by the same identity.
+type GitHubTransport struct {
+	Transport http.RoundTripper
+	Logger    *log.Logger
+	MaxWait   time.Duration
+
+	mu     sync.Mutex
+	tokens []*githubToken
+	next   int
+	stats  GitHubStats
+}
+
+// GitHubStats counts the requests made through a GitHubTransport.
+type GitHubStats struct {
+	Requests    int
+	RateLimited int
+	Waited      time.Duration
+}
+
+type githubToken struct {
+	value string
+	// remaining is -1 until the first response for the token is seen
+	remaining int
+	reset     time.Time
+}
+
+// NewGitHubTransport returns a transport that uses tokens in turn.  With no tokens requests are sent unchanged.
+func NewGitHubTransport(transport http.RoundTripper, tokens []string, logger *log.Logger) *GitHubTransport {
+	t := &GitHubTransport{
+		Transport: transport,
+		Logger:    logger,
+		MaxWait:   DefaultGitHubMaxWait,
+	}
+	for _, v := range tokens {
+		t.tokens = append(t.tokens, &githubToken{value: v, remaining: -1})
+	}
+	return t
+}
+
+// GitHubTokens returns the value of GITHUB_TOKEN followed by the comma separated GITHUB_TOKENS, which can hold
+// additional personal access tokens.  Tokens are read once and never refreshed, so short lived tokens such as GitHub
+// App installation tokens are only suitable for runs that finish before they expire.
+func GitHubTokens() []string {
+	var tokens []string
+	seen := map[string]bool{}
+	for _, v := range append([]string{os.Getenv("GITHUB_TOKEN")}, strings.Split(os.Getenv("GITHUB_TOKENS"), ",")...) {
+		v = strings.TrimSpace(v)
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		tokens = append(tokens, v)
+	}
+	return tokens
+}
+
+// Stats returns the counts of requests made so far.
+func (t *GitHubTransport) Stats() GitHubStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.stats
+}
+
+// RoundTrip sends the request with the current token, or the first token for writes, unless it is rate limited.
+func (t *GitHubTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	write := isWrite(req)
+	// every rate limited response exhausts a token, so after trying them all only a wait for a reset can help
+	maxAttempts := len(t.tokens) + 1
+	sent := false
+	for attempt := 0; ; attempt++ {
+		tok, wait := t.pick(write)
+		if tok == nil && wait > 0 {
+			if wait > t.MaxWait {
+				return nil, fmt.Errorf("all GitHub tokens are rate limited for another %s", wait.Round(time.Second))
+			}
+			t.Logger.Printf("all GitHub tokens are rate limited, waiting %s for the limit to reset", wait.Round(time.Second))
+			select {
+			case <-req.Context().Done():
+				return nil, req.Context().Err()
+			case <-time.After(wait):
+			}
+			t.mu.Lock()
+			t.stats.Waited += wait
+			t.mu.Unlock()
+			continue
+		}
+
+		r := req.Clone(req.Context())
+		// the body is only consumed once it has been sent, retries are only made when it can be rewound
+		if sent && req.Body != nil && req.Body != http.NoBody {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r.Body = body
+		}
+		if tok != nil {
+			r.Header.Set("Authorization", "Bearer "+tok.value)
+		}
+
+		resp, err := t.Transport.RoundTrip(r)
+		if err != nil {
+			return nil, err
+		}
+		sent = true
+
+		limited := t.record(tok, resp)
+		canRetry := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+		if !limited || tok == nil || !canRetry || attempt+1 >= maxAttempts {
+			return resp, nil
+		}
+		resp.Body.Close()
+	}
+}
+
+// isWrite reports whether the request changes something on GitHub.  GraphQL is only used for queries.
+func isWrite(req *http.Request) bool {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return false
+	}
+	return !strings.HasSuffix(req.URL.Path, "/graphql")
+}
+
+// pick returns the current token, or the first for writes, if it has requests remaining, otherwise the next token
+// that does.  When none do it returns how long until the first rate limited token resets.
+func (t *GitHubTransport) pick(write bool) (*githubToken, time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.tokens) == 0 {
+		return nil, 0
+	}
+
+	now := time.Now()
+	if write {
+		tok := t.tokens[0]
+		if tok.remaining == 0 && !now.Before(tok.reset) {
+			tok.remaining = -1
+		}
+		if tok.remaining != 0 {
+			return tok, 0
+		}
+		return nil, tok.reset.Sub(now)
+	}
+
+	var wait time.Duration
+	for i := range t.tokens {
+		tok := t.tokens[(t.next+i)%len(t.tokens)]
+		if tok.remaining == 0 && !now.Before(tok.reset) {
+			tok.remaining = -1
+		}
+		if tok.remaining != 0 {
+			t.next = (t.next + i) % len(t.tokens)
+			return tok, 0
+		}
+		if d := tok.reset.Sub(now); wait == 0 || d < wait {
+			wait = d
+		}
+	}
+	return nil, wait
+}
+
+// record updates the rate limit of the token from the response headers and reports whether the request was rejected
+// by a primary or secondary rate limit.
+func (t *GitHubTransport) record(tok *githubToken, resp *http.Response) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.stats.Requests++
+
+	remaining := resp.Header.Get("X-RateLimit-Remaining")
+	retryAfter := resp.Header.Get("Retry-After")
+	limited := (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests) &&
+		(remaining == "0" || retryAfter != "")
+	if limited {
+		t.stats.RateLimited++
+	}
+	if tok == nil {
+		return limited
+	}
+
+	if n, err := strconv.Atoi(remaining); err == nil {
+		tok.remaining = n
+	}
+	if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		tok.reset = time.Unix(reset, 0)
+	}
+	// secondary rate limits apply even with requests remaining and say when to retry instead
+	if seconds, err := strconv.Atoi(retryAfter); err == nil && limited {
+		tok.remaining = 0
+		tok.reset = time.Now().Add(time.Duration(seconds) * time.Second)
+	}
+	if limited {
+		t.Logger.Printf("GitHub token %d of %d is rate limited until %s", t.indexOf(tok)+1, len(t.tokens), tok.reset.Format(time.RFC3339))
+	}
+
+	return limited
+}
+
+func (t *GitHubTransport) indexOf(tok *githubToken) int {
+	for i := range t.tokens {
+		if t.tokens[i] == tok {
+			return i
+		}
+	}
+	return -1
+}
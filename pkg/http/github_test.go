@@ -0,0 +1,187 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rateLimitServer rejects requests made with a token in limited, recording the authorization and body of each request.
+func rateLimitServer(t *testing.T, limited map[string]http.Header) (*httptest.Server, *[]string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var seen []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		auth := r.Header.Get("Authorization")
+		mu.Lock()
+		seen = append(seen, fmt.Sprintf("%s %s", auth, b))
+		mu.Unlock()
+
+		if h, ok := limited[auth]; ok {
+			for k, v := range h {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		_, _ = w.Write([]byte("ok"))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &seen
+}
+
+func TestGitHubTransport_rotatesRateLimitedTokens(t *testing.T) {
+	reset := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	server, seen := rateLimitServer(t, map[string]http.Header{
+		"Bearer one": {"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {reset}},
+	})
+
+	transport := NewGitHubTransport(http.DefaultTransport, []string{"one", "two"}, log.New(io.Discard, "", 0))
+	client := &http.Client{Transport: transport}
+
+	for i := 0; i < 2; i++ {
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/graphql", bytes.NewBufferString("query"))
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+
+	// the first token is skipped once it is known to be rate limited
+	assert.Equal(t, []string{"Bearer one query", "Bearer two query", "Bearer two query"}, *seen)
+	assert.Equal(t, GitHubStats{Requests: 3, RateLimited: 1}, transport.Stats())
+}
+
+func TestGitHubTransport_waitsForReset(t *testing.T) {
+	server, seen := rateLimitServer(t, map[string]http.Header{
+		"Bearer one": {"Retry-After": {"1"}},
+	})
+
+	transport := NewGitHubTransport(http.DefaultTransport, []string{"one"}, log.New(io.Discard, "", 0))
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	// with a single token the request is retried once after waiting, then the response is returned as is
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, []string{"Bearer one ", "Bearer one "}, *seen)
+	stats := transport.Stats()
+	assert.Equal(t, 2, stats.RateLimited)
+	assert.Greater(t, stats.Waited, time.Duration(0))
+}
+
+func TestGitHubTransport_maxWait(t *testing.T) {
+	reset := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	server, seen := rateLimitServer(t, map[string]http.Header{
+		"Bearer one": {"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {reset}},
+	})
+
+	transport := NewGitHubTransport(http.DefaultTransport, []string{"one"}, log.New(io.Discard, "", 0))
+	transport.MaxWait = time.Minute
+	client := &http.Client{Transport: transport}
+
+	for i := 0; i < 2; i++ {
+		_, err := client.Get(server.URL) //nolint:bodyclose // the request fails before a response is returned
+		assert.ErrorContains(t, err, "all GitHub tokens are rate limited for another")
+	}
+	// the second request is not sent at all
+	assert.Len(t, *seen, 1)
+}
+
+func TestGitHubTransport_noTokens(t *testing.T) {
+	server, seen := rateLimitServer(t, nil)
+
+	client := &http.Client{Transport: NewGitHubTransport(http.DefaultTransport, nil, log.New(io.Discard, "", 0))}
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, []string{" "}, *seen)
+}
+
+func TestGitHubTokens(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "one")
+	t.Setenv("GITHUB_TOKENS", "two, one,,three")
+
+	assert.Equal(t, []string{"one", "two", "three"}, GitHubTokens())
+}
+
+func TestGitHubTransport_exhaustedWithUnrewindableBody(t *testing.T) {
+	server, seen := rateLimitServer(t, nil)
+
+	transport := NewGitHubTransport(http.DefaultTransport, []string{"one"}, log.New(io.Discard, "", 0))
+	transport.tokens[0].remaining = 0
+	transport.tokens[0].reset = time.Now().Add(time.Second)
+
+	// a plain reader has no GetBody, it must not be needed when the first attempt only waited
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/graphql", io.MultiReader(bytes.NewBufferString("query")))
+	require.NoError(t, err)
+	require.Nil(t, req.GetBody)
+
+	resp, err := transport.RoundTrip(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{"Bearer one query"}, *seen)
+	assert.Greater(t, transport.Stats().Waited, time.Duration(0))
+}
+
+func TestGitHubTransport_staysOnToken(t *testing.T) {
+	server, seen := rateLimitServer(t, nil)
+
+	client := &http.Client{Transport: NewGitHubTransport(http.DefaultTransport, []string{"one", "two"}, log.New(io.Discard, "", 0))}
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	assert.Equal(t, []string{"Bearer one ", "Bearer one ", "Bearer one "}, *seen)
+}
+
+func TestGitHubTransport_writesUseFirstToken(t *testing.T) {
+	server, seen := rateLimitServer(t, nil)
+
+	transport := NewGitHubTransport(http.DefaultTransport, []string{"one", "two"}, log.New(io.Discard, "", 0))
+	transport.tokens[0].remaining = 0
+	transport.tokens[0].reset = time.Now().Add(time.Second)
+	client := &http.Client{Transport: transport}
+
+	requests := []struct{ method, path, body string }{
+		{http.MethodGet, "/repos/wolfi-dev/os/pulls", ""},
+		{http.MethodPost, "/graphql", "query"},
+		{http.MethodPost, "/repos/wolfi-dev/os/pulls", "create"},
+		{http.MethodPatch, "/repos/wolfi-dev/os/pulls/1", "close"},
+	}
+	for _, r := range requests {
+		req, err := http.NewRequest(r.method, server.URL+r.path, bytes.NewBufferString(r.body))
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	// reads move on to the second token while writes wait for the first to reset
+	assert.Equal(t, []string{"Bearer two ", "Bearer two query", "Bearer one create", "Bearer one close"}, *seen)
+	assert.Greater(t, transport.Stats().Waited, time.Duration(0))
+}
@@ -0,0 +1,127 @@
+package index
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/chainguard-dev/go-apk/pkg/apk"
+	"github.com/wolfi-dev/wolfictl/pkg/versions"
+)
+
+// Change is a package whose latest version differs between two indexes.
+type Change struct {
+	Name       string `json:"name"`
+	OldVersion string `json:"oldVersion,omitempty"`
+	NewVersion string `json:"newVersion,omitempty"`
+}
+
+// Diff describes how the latest version of each package changed between two
+// indexes. Changed holds packages whose version changed but can't be ordered
+// because either version fails to parse.
+type Diff struct {
+	Added      []Change `json:"added"`
+	Removed    []Change `json:"removed"`
+	Upgraded   []Change `json:"upgraded"`
+	Downgraded []Change `json:"downgraded"`
+	Changed    []Change `json:"changed"`
+}
+
+// DiffIndexes compares the latest version of every package in oldIdx with
+// newIdx. Each list in the result is sorted by package name.
+func DiffIndexes(oldIdx, newIdx *apk.APKIndex) Diff {
+	oldVersions := latestVersions(oldIdx)
+	newVersions := latestVersions(newIdx)
+
+	d := Diff{
+		Added:      []Change{},
+		Removed:    []Change{},
+		Upgraded:   []Change{},
+		Downgraded: []Change{},
+		Changed:    []Change{},
+	}
+	for name, newVersion := range newVersions {
+		oldVersion, ok := oldVersions[name]
+		change := Change{Name: name, OldVersion: oldVersion, NewVersion: newVersion}
+		switch {
+		case !ok:
+			d.Added = append(d.Added, change)
+		case isNewer(newVersion, oldVersion):
+			d.Upgraded = append(d.Upgraded, change)
+		case isNewer(oldVersion, newVersion):
+			d.Downgraded = append(d.Downgraded, change)
+		case oldVersion != newVersion:
+			d.Changed = append(d.Changed, change)
+		}
+	}
+	for name, oldVersion := range oldVersions {
+		if _, ok := newVersions[name]; !ok {
+			d.Removed = append(d.Removed, Change{Name: name, OldVersion: oldVersion})
+		}
+	}
+
+	for _, changes := range [][]Change{d.Added, d.Removed, d.Upgraded, d.Downgraded, d.Changed} {
+		sort.Slice(changes, func(i, j int) bool {
+			return changes[i].Name < changes[j].Name
+		})
+	}
+
+	return d
+}
+
+// WriteMarkdown writes the diff as markdown suitable for release notes.
+func (d Diff) WriteMarkdown(w io.Writer) error {
+	sections := []struct {
+		title   string
+		changes []Change
+	}{
+		{"Added", d.Added},
+		{"Removed", d.Removed},
+		{"Upgraded", d.Upgraded},
+		{"Downgraded", d.Downgraded},
+		{"Changed", d.Changed},
+	}
+
+	for _, s := range sections {
+		if len(s.changes) == 0 {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "## %s\n\n", s.title); err != nil {
+			return err
+		}
+		for _, c := range s.changes {
+			var err error
+			switch {
+			case c.OldVersion == "":
+				_, err = fmt.Fprintf(w, "- %s %s\n", c.Name, c.NewVersion)
+			case c.NewVersion == "":
+				_, err = fmt.Fprintf(w, "- %s %s\n", c.Name, c.OldVersion)
+			default:
+				_, err = fmt.Fprintf(w, "- %s %s → %s\n", c.Name, c.OldVersion, c.NewVersion)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func latestVersions(idx *apk.APKIndex) map[string]string {
+	latest := map[string]string{}
+	for _, p := range idx.Packages {
+		if v, ok := latest[p.Name]; !ok || isNewer(p.Version, v) {
+			latest[p.Name] = p.Version
+		}
+	}
+	return latest
+}
+
+// isNewer reports whether apk version a is newer than b.
+func isNewer(a, b string) bool {
+	return versions.ByLatestStrings{a, b}.Less(0, 1)
+}
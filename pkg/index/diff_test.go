@@ -0,0 +1,69 @@
+package index
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/chainguard-dev/go-apk/pkg/apk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffIndexes(t *testing.T) {
+	oldIdx := &apk.APKIndex{Packages: []*apk.Package{
+		{Name: "bash", Version: "5.2.15-r0"},
+		{Name: "bash", Version: "5.2.15-r1"},
+		{Name: "curl", Version: "8.1.0-r0"},
+		{Name: "git", Version: "2.42.0-r0"},
+		{Name: "go-1.20", Version: "1.20.9-r0"},
+		{Name: "snapshot", Version: "latest"},
+		{Name: "zlib", Version: "1.3-r0"},
+	}}
+	newIdx := &apk.APKIndex{Packages: []*apk.Package{
+		{Name: "bash", Version: "5.2.15-r1"},
+		{Name: "bash", Version: "5.2.15-r10"},
+		{Name: "curl", Version: "8.0.1-r0"},
+		{Name: "git", Version: "2.42.0-r0"},
+		{Name: "go-1.21", Version: "1.21.3-r0"},
+		{Name: "snapshot", Version: "2024.1.0-r0"},
+		{Name: "zlib", Version: "1.3.1-r0"},
+	}}
+
+	got := DiffIndexes(oldIdx, newIdx)
+	assert.Equal(t, Diff{
+		Added:   []Change{{Name: "go-1.21", NewVersion: "1.21.3-r0"}},
+		Removed: []Change{{Name: "go-1.20", OldVersion: "1.20.9-r0"}},
+		Upgraded: []Change{
+			{Name: "bash", OldVersion: "5.2.15-r1", NewVersion: "5.2.15-r10"},
+			{Name: "zlib", OldVersion: "1.3-r0", NewVersion: "1.3.1-r0"},
+		},
+		Downgraded: []Change{{Name: "curl", OldVersion: "8.1.0-r0", NewVersion: "8.0.1-r0"}},
+		// latest can't be parsed, so the change is reported without saying which way it went
+		Changed: []Change{{Name: "snapshot", OldVersion: "latest", NewVersion: "2024.1.0-r0"}},
+	}, got)
+
+	var buf bytes.Buffer
+	require.NoError(t, got.WriteMarkdown(&buf))
+	assert.Equal(t, `## Added
+
+- go-1.21 1.21.3-r0
+
+## Removed
+
+- go-1.20 1.20.9-r0
+
+## Upgraded
+
+- bash 5.2.15-r1 → 5.2.15-r10
+- zlib 1.3-r0 → 1.3.1-r0
+
+## Downgraded
+
+- curl 8.1.0-r0 → 8.0.1-r0
+
+## Changed
+
+- snapshot latest → 2024.1.0-r0
+
+`, buf.String())
+}
@@ -11,10 +11,17 @@ import (
 )
 
 func Index(arch, repo string) (*apk.APKIndex, error) {
+	if isURL(repo) {
+		return Open(fmt.Sprintf("%s/%s/APKINDEX.tar.gz", repo, arch))
+	}
+	return Open(repo)
+}
+
+// Open reads an APKINDEX.tar.gz from a local path or URL.
+func Open(location string) (*apk.APKIndex, error) {
 	var rc io.ReadCloser
-	if strings.HasPrefix(repo, "http://") || strings.HasPrefix(repo, "https://") {
-		url := fmt.Sprintf("%s/%s/APKINDEX.tar.gz", repo, arch)
-		resp, err := http.Get(url) //nolint:gosec
+	if isURL(location) {
+		resp, err := http.Get(location) //nolint:gosec
 		if err != nil {
 			return nil, err
 		}
@@ -24,13 +31,13 @@ func Index(arch, repo string) (*apk.APKIndex, error) {
 			if err != nil {
 				return nil, err
 			}
-			return nil, fmt.Errorf("GET %s (%d): %s", url, resp.StatusCode, b)
+			return nil, fmt.Errorf("GET %s (%d): %s", location, resp.StatusCode, b)
 		}
 		rc = resp.Body
 	} else {
-		f, err := os.Open(repo)
+		f, err := os.Open(location)
 		if err != nil {
-			return nil, fmt.Errorf("opening %q: %w", repo, err)
+			return nil, fmt.Errorf("opening %q: %w", location, err)
 		}
 		defer f.Close()
 		rc = f
@@ -38,3 +45,7 @@ func Index(arch, repo string) (*apk.APKIndex, error) {
 
 	return apk.IndexFromArchive(rc)
 }
+
+func isURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
@@ -32,7 +32,7 @@ func New(opts ...Option) *Linter {
 // Lint evaluates all rules and returns the result.
 func (l *Linter) Lint(ctx context.Context) (Result, error) {
 	log := clog.FromContext(ctx)
-	rules := AllRules(l)
+	rules := l.rules()
 
 	namesToPkg, err := melange.ReadAllPackagesFromRepo(ctx, l.options.Path)
 	if err != nil {
@@ -105,6 +105,27 @@ func (l *Linter) Lint(ctx context.Context) (Result, error) {
 	return results, nil
 }
 
+// rules returns all rules with any configured severity overrides applied.
+func (l *Linter) rules() Rules {
+	rules := AllRules(l)
+	for i := range rules {
+		if sev, ok := l.options.Severities[rules[i].Name]; ok {
+			rules[i].Severity = sev
+		}
+	}
+	return rules
+}
+
+// RuleNames returns the names of all available rules.
+func (l *Linter) RuleNames() []string {
+	rules := AllRules(l)
+	names := make([]string, 0, len(rules))
+	for _, r := range rules {
+		names = append(names, r.Name)
+	}
+	return names
+}
+
 // Print prints the result to stdout.
 func (l *Linter) Print(ctx context.Context, result Result) {
 	log := clog.FromContext(ctx)
@@ -124,7 +145,7 @@ func (l *Linter) Print(ctx context.Context, result Result) {
 func (l *Linter) PrintRules(ctx context.Context) {
 	log := clog.FromContext(ctx)
 	log.Info("Available rules:")
-	for _, rule := range AllRules(l) {
-		log.Infof("* %s: %s\n", rule.Name, cases.Title(language.Und).String(rule.Description))
+	for _, rule := range l.rules() {
+		log.Infof("* %s: %s (%s)\n", rule.Name, cases.Title(language.Und).String(rule.Description), rule.Severity)
 	}
 }
@@ -7,6 +7,9 @@ type Options struct {
 
 	// Skip rules removes the given slice of rules to be checked
 	SkipRules []string
+
+	// Severities overrides the default severity of rules, keyed by rule name
+	Severities map[string]Severity
 }
 
 // Option represents a linter option.
@@ -25,3 +28,10 @@ func WithSkipRules(skipRules []string) Option {
 		o.SkipRules = skipRules
 	}
 }
+
+// WithSeverities overrides the severity of the named rules.
+func WithSeverities(severities map[string]Severity) Option {
+	return func(o *Options) {
+		o.Severities = severities
+	}
+}
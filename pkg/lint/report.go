@@ -0,0 +1,198 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// quotedValue matches the quoted values rules put in their messages, such as the offending URI.
+var quotedValue = regexp.MustCompile(`"([^"\\]+)"`)
+
+// Finding is a single rule violation, as written by PrintJSON.
+type Finding struct {
+	Package  string   `json:"package"`
+	File     string   `json:"file"`
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+// Findings flattens the result into one Finding per rule violation.
+func (l *Linter) Findings(result Result) []Finding {
+	findings := []Finding{}
+	for _, res := range result {
+		for _, e := range res.Errors {
+			findings = append(findings, Finding{
+				Package:  res.File,
+				File:     l.configFile(res.File),
+				Rule:     e.Rule.Name,
+				Severity: e.Rule.Severity,
+				Message:  e.message(),
+			})
+		}
+	}
+	return findings
+}
+
+// PrintJSON writes the result to w as a JSON array of findings.
+func (l *Linter) PrintJSON(w io.Writer, result Result) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(l.Findings(result))
+}
+
+// PrintSARIF writes the result to w as a SARIF 2.1.0 log, which GitHub code
+// scanning can use to annotate pull requests. Rules only report a message, not
+// the YAML node they object to, so a finding is placed on the first line that
+// contains a value quoted in its message, or on line 1 of the config.
+func (l *Linter) PrintSARIF(w io.Writer, result Result) error {
+	rules := l.rules()
+	driver := sarifDriver{
+		Name:           "wolfictl-lint",
+		InformationURI: "https://github.com/wolfi-dev/wolfictl",
+		Rules:          make([]sarifRule, 0, len(rules)),
+	}
+	for _, rule := range rules {
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:                   rule.Name,
+			ShortDescription:     sarifMessage{Text: rule.Description},
+			DefaultConfiguration: sarifRuleConfiguration{Level: rule.Severity.sarifLevel()},
+		})
+	}
+
+	results := []sarifResult{}
+	lines := map[string][]string{}
+	for _, f := range l.Findings(result) {
+		if _, ok := lines[f.File]; !ok {
+			// a config that can't be read still gets its findings, on line 1
+			data, _ := os.ReadFile(f.File)
+			lines[f.File] = strings.Split(string(data), "\n")
+		}
+		results = append(results, sarifResult{
+			RuleID:  f.Rule,
+			Level:   f.Severity.sarifLevel(),
+			Message: sarifMessage{Text: f.Message},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(f.File)},
+					Region:           sarifRegion{StartLine: findingLine(lines[f.File], f.Message)},
+				},
+			}},
+		})
+	}
+
+	log := sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
+
+// configFile returns the path of the config for the named package. Configs in
+// a directory are expected to be named after the package they define.
+func (l *Linter) configFile(name string) string {
+	if fi, err := os.Stat(l.options.Path); err == nil && !fi.IsDir() {
+		return l.options.Path
+	}
+	return filepath.Join(l.options.Path, name+".yaml")
+}
+
+// message returns the error message without the rule name and severity that
+// Lint decorates it with.
+func (e EvalRuleError) message() string {
+	msg := e.Error.Error()
+	msg = strings.TrimPrefix(msg, fmt.Sprintf("[%s]: ", e.Rule.Name))
+	return strings.TrimSuffix(msg, fmt.Sprintf(" (%s)", e.Rule.Severity))
+}
+
+// findingLine returns the 1-based line of the first value quoted in the message
+// that appears in the config, or 1 when none do.
+func findingLine(lines []string, message string) int {
+	for _, m := range quotedValue.FindAllStringSubmatch(message, -1) {
+		for i, line := range lines {
+			if strings.Contains(line, m[1]) {
+				return i + 1
+			}
+		}
+	}
+	return 1
+}
+
+func (s Severity) sarifLevel() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string                 `json:"id"`
+	ShortDescription     sarifMessage           `json:"shortDescription"`
+	DefaultConfiguration sarifRuleConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifRuleConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
@@ -0,0 +1,106 @@
+package lint
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinter_Severities(t *testing.T) {
+	ctx := context.Background()
+	l := New(
+		WithPath("testdata/files/missing-copyright.yaml"),
+		WithSeverities(map[string]Severity{"valid-copyright-header": SeverityWarning}),
+	)
+	got, err := l.Lint(ctx)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	require.Len(t, got[0].Errors, 1)
+
+	e := got[0].Errors[0]
+	assert.Equal(t, SeverityWarning, e.Rule.Severity)
+	assert.EqualError(t, e.Error, "[valid-copyright-header]: copyright header is missing (WARNING)")
+
+	// a downgraded rule no longer fails a run that only fails on errors
+	assert.True(t, got.HasErrorsAtOrAbove(SeverityWarning))
+	assert.False(t, got.HasErrorsAtOrAbove(SeverityError))
+}
+
+func TestLinter_HasErrorsAtOrAbove(t *testing.T) {
+	ctx := context.Background()
+	l := New(
+		WithPath("testdata/files/missing-copyright.yaml"),
+		WithSeverities(map[string]Severity{"valid-copyright-header": SeverityError}),
+	)
+	got, err := l.Lint(ctx)
+	require.NoError(t, err)
+
+	assert.True(t, got.HasErrorsAtOrAbove(SeverityError))
+	assert.True(t, got.HasErrorsAtOrAbove(SeverityInfo))
+	assert.False(t, Result{}.HasErrorsAtOrAbove(SeverityInfo))
+}
+
+func TestLinter_RuleNames(t *testing.T) {
+	names := New().RuleNames()
+	assert.Contains(t, names, "valid-copyright-header")
+	assert.NotContains(t, names, "valid-copyrigth-header")
+}
+
+func TestLinter_PrintJSON(t *testing.T) {
+	ctx := context.Background()
+	l := newTestLinterWithFile("missing-copyright.yaml")
+	result, err := l.Lint(ctx)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, l.PrintJSON(&buf, result))
+
+	var got []Finding
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	assert.Equal(t, []Finding{{
+		Package:  "missing-copyright",
+		File:     "testdata/files/missing-copyright.yaml",
+		Rule:     "valid-copyright-header",
+		Severity: SeverityInfo,
+		Message:  "copyright header is missing",
+	}}, got)
+}
+
+func TestLinter_findingLine(t *testing.T) {
+	lines := []string{"package:", "  name: foo", "pipeline:", "  - uses: fetch", "    with:", "      uri: https://example.org/foo.tar.gz"}
+
+	assert.Equal(t, 6, findingLine(lines, `"example.org" shares components with "example.com"`))
+	assert.Equal(t, 2, findingLine(lines, `"bar", "foo" is not allowed`))
+	assert.Equal(t, 1, findingLine(lines, "copyright header is missing"))
+}
+
+func TestLinter_PrintSARIF(t *testing.T) {
+	ctx := context.Background()
+	l := newTestLinterWithDir("dirs/tld-swap/")
+	result, err := l.Lint(ctx)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, l.PrintSARIF(&buf, result))
+
+	var got sarifLog
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	assert.Equal(t, "2.1.0", got.Version)
+	require.Len(t, got.Runs, 1)
+	assert.Len(t, got.Runs[0].Tool.Driver.Rules, len(AllRules(l)))
+	assert.Equal(t, []sarifResult{{
+		RuleID:  "uri-mimic",
+		Level:   "error",
+		Message: sarifMessage{Text: `"test.org" shares components with "test.com"`},
+		Locations: []sarifLocation{{
+			PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: "testdata/dirs/tld-swap/tld-swap.yaml"},
+				Region:           sarifRegion{StartLine: 15},
+			},
+		}},
+	}}, got.Runs[0].Results)
+}
@@ -331,6 +331,22 @@ var AllRules = func(l *Linter) Rules { //nolint:gocyclo
 				return nil
 			},
 		},
+		{
+			Name:        "valid-pipeline-step",
+			Description: "every pipeline step should use a pipeline or run a script",
+			Severity:    SeverityError,
+			LintFunc: func(config config.Configuration) error {
+				if err := checkPipelineSteps(config.Pipeline); err != nil {
+					return err
+				}
+				for _, subPkg := range config.Subpackages {
+					if err := checkPipelineSteps(subPkg.Pipeline); err != nil {
+						return fmt.Errorf("subpackage %s: %w", subPkg.Name, err)
+					}
+				}
+				return nil
+			},
+		},
 		{
 			Name:        "check-when-version-changes",
 			Description: "check comments to make sure they are updated when version changes",
@@ -413,6 +429,19 @@ var AllRules = func(l *Linter) Rules { //nolint:gocyclo
 	}
 }
 
+func checkPipelineSteps(pipeline []config.Pipeline) error {
+	for i, p := range pipeline {
+		// melange itself rejects steps with both uses and runs
+		if p.Uses == "" && p.Runs == "" && len(p.Pipeline) == 0 {
+			return fmt.Errorf("pipeline step %d has no uses, runs or pipeline", i)
+		}
+		if err := checkPipelineSteps(p.Pipeline); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func containsKey(parentNode *yaml.Node, key string) error {
 	it := yit.FromNode(parentNode).
 		ValuesForMap(yit.WithValue(key), yit.All)
@@ -224,6 +224,22 @@ func TestLinter_Rules(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			file: "invalid-pipeline-step.yaml",
+			want: EvalResult{
+				File: "invalid-pipeline-step",
+				Errors: EvalRuleErrors{
+					{
+						Rule: Rule{
+							Name:     "valid-pipeline-step",
+							Severity: SeverityError,
+						},
+						Error: fmt.Errorf("[valid-pipeline-step]: pipeline step 1 has no uses, runs or pipeline (ERROR)"),
+					},
+				},
+			},
+			wantErr: false,
+		},
 		{
 			file: "nolint.yaml",
 			want: EvalResult{
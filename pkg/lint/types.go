@@ -2,6 +2,8 @@ package lint
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 
 	"chainguard.dev/melange/pkg/config"
 )
@@ -21,6 +23,28 @@ const (
 	SeverityInfo    Severity = "INFO"
 )
 
+// ParseSeverity parses a severity name, ignoring case.
+func ParseSeverity(s string) (Severity, error) {
+	switch sev := Severity(strings.ToUpper(s)); sev {
+	case SeverityError, SeverityWarning, SeverityInfo:
+		return sev, nil
+	default:
+		return "", fmt.Errorf("invalid severity %q, must be one of %s, %s or %s", s, SeverityError, SeverityWarning, SeverityInfo)
+	}
+}
+
+// rank orders severities from info to error, unknown severities rank as errors.
+func (s Severity) rank() int {
+	switch s {
+	case SeverityInfo:
+		return 1
+	case SeverityWarning:
+		return 2
+	default:
+		return 3
+	}
+}
+
 // Rule represents a linter rule.
 type Rule struct {
 	// Name is the name of the rule.
@@ -76,6 +100,19 @@ func (r Result) HasErrors() bool {
 	return false
 }
 
+// HasErrorsAtOrAbove returns true if any of the EvalResult has an error from a
+// rule with at least the given severity.
+func (r Result) HasErrorsAtOrAbove(threshold Severity) bool {
+	for _, res := range r {
+		for _, e := range res.Errors {
+			if e.Error != nil && e.Rule.Severity.rank() >= threshold.rank() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // WrapErrors wraps multiple errors into a single error.
 func (e EvalRuleErrors) WrapErrors() error {
 	errs := []error{}
@@ -14,6 +14,7 @@ import (
 	"chainguard.dev/melange/pkg/renovate/bump"
 
 	"chainguard.dev/melange/pkg/config"
+	"github.com/chainguard-dev/clog"
 )
 
 const yamlExtension = ".yaml"
@@ -151,7 +152,7 @@ func ReadAllPackagesFromRepo(ctx context.Context, dir string) (map[string]*Packa
 			NoLint:   nolint,
 		}
 	}
-	fmt.Printf("found %[1]d packages\n", len(p))
+	clog.FromContext(ctx).Infof("found %d packages", len(p))
 	return p, nil
 }
 
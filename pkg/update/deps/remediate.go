@@ -0,0 +1,323 @@
+package deps
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"chainguard.dev/melange/pkg/config"
+	"github.com/google/osv-scanner/pkg/models"
+	"golang.org/x/exp/slices"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+	"gopkg.in/yaml.v3"
+)
+
+// ModuleFix describes a Go module that needs to be bumped to remediate one or
+// more vulnerabilities.
+type ModuleFix struct {
+	Path           string
+	CurrentVersion string
+	// FixedVersion is the lowest version that fixes every vulnerability with a
+	// known fix. It is empty if none of the vulnerabilities have been fixed.
+	FixedVersion string
+	// Vulnerabilities lists the IDs of the vulnerabilities affecting CurrentVersion.
+	Vulnerabilities []string
+	// Unfixed lists the IDs of the vulnerabilities that have no fixed version.
+	Unfixed []string
+}
+
+// FindGoModuleFixes returns the modules required by modFile that are affected
+// by the given OSV vulnerabilities, along with the minimum version that fixes
+// them. bumped holds versions already set by a go/bump pipeline, which take
+// precedence over the go.mod when they are newer.
+func FindGoModuleFixes(modFile *modfile.File, bumped map[string]string, vulns []models.Vulnerability) []ModuleFix {
+	replaced := map[string]bool{}
+	for _, replace := range modFile.Replace {
+		if replace != nil {
+			replaced[replace.Old.Path] = true
+		}
+	}
+
+	var fixes []ModuleFix
+	for _, require := range modFile.Require {
+		if require == nil {
+			continue
+		}
+		modPath := require.Mod.Path
+		if replaced[modPath] {
+			log.Printf("skipping %s as it is replaced in the go.mod", modPath)
+			continue
+		}
+
+		current := require.Mod.Version
+		if v, ok := bumped[modPath]; ok && semver.Compare(v, current) > 0 {
+			current = v
+		}
+
+		fix := ModuleFix{Path: modPath, CurrentVersion: current}
+		for i := range vulns {
+			fixed, affected := fixedVersionFor(&vulns[i], modPath, current)
+			if !affected {
+				continue
+			}
+			fix.Vulnerabilities = append(fix.Vulnerabilities, vulns[i].ID)
+			if fixed == "" {
+				fix.Unfixed = append(fix.Unfixed, vulns[i].ID)
+				continue
+			}
+			if semver.Compare(fixed, fix.FixedVersion) > 0 {
+				fix.FixedVersion = fixed
+			}
+		}
+
+		if len(fix.Vulnerabilities) > 0 {
+			fixes = append(fixes, fix)
+		}
+	}
+
+	sort.Slice(fixes, func(i, j int) bool {
+		return fixes[i].Path < fixes[j].Path
+	})
+
+	return fixes
+}
+
+// fixedVersionFor reports whether version of the Go module modPath is affected
+// by vuln and, if so, the version that fixes it.
+func fixedVersionFor(vuln *models.Vulnerability, modPath, version string) (fixed string, affected bool) {
+	for _, a := range vuln.Affected {
+		if a.Package.Ecosystem != models.EcosystemGo || a.Package.Name != modPath {
+			continue
+		}
+
+		for _, r := range a.Ranges {
+			if r.Type != models.RangeSemVer {
+				continue
+			}
+
+			introduced := ""
+			open := false
+			for _, e := range r.Events {
+				switch {
+				case e.Introduced != "":
+					introduced = canonicalGoVersion(e.Introduced)
+					open = true
+				case e.Fixed != "" && open:
+					open = false
+					if inRange(version, introduced) && semver.Compare(version, canonicalGoVersion(e.Fixed)) < 0 {
+						return canonicalGoVersion(e.Fixed), true
+					}
+				case e.LastAffected != "" && open:
+					open = false
+					if inRange(version, introduced) && semver.Compare(version, canonicalGoVersion(e.LastAffected)) <= 0 {
+						return "", true
+					}
+				}
+			}
+			if open && inRange(version, introduced) {
+				return "", true
+			}
+		}
+
+		for _, v := range a.Versions {
+			if canonicalGoVersion(v) == version {
+				return "", true
+			}
+		}
+	}
+
+	return "", false
+}
+
+func inRange(version, introduced string) bool {
+	return introduced == "" || semver.Compare(version, introduced) >= 0
+}
+
+// canonicalGoVersion converts an OSV Go version (which omits the leading "v")
+// to the form used in go.mod files. The OSV "0" introduced event maps to the
+// empty string, which sorts before every version.
+func canonicalGoVersion(v string) string {
+	if v == "0" {
+		return ""
+	}
+	if !strings.HasPrefix(v, "v") {
+		return "v" + v
+	}
+	return v
+}
+
+// RemediateGoBumpDeps checks out the sources of the package and rewrites its
+// go/bump pipelines so that every Go module affected by the given
+// vulnerabilities is bumped to its fixed version. If the package has no go/bump
+// pipeline, one is added after the git-checkout step. The returned fixes
+// describe every affected module, including those without a known fix.
+func RemediateGoBumpDeps(doc *yaml.Node, updated *config.Configuration, vulns []models.Vulnerability, mutations map[string]string) ([]ModuleFix, error) {
+	tempDir, err := os.MkdirTemp("", "wolfibump")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary folder to clone package configs into: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	checkedOut := false
+	for i := range updated.Pipeline {
+		if updated.Pipeline[i].Uses == "git-checkout" {
+			destinationDir := tempDir
+			if dest := updated.Pipeline[i].With["destination"]; dest != "" {
+				destinationDir = path.Join(tempDir, dest)
+			}
+			if err := gitCheckout(&updated.Pipeline[i], destinationDir, mutations); err != nil {
+				return nil, fmt.Errorf("failed to git checkout the repository: %v", err)
+			}
+			checkedOut = true
+		}
+	}
+	if !checkedOut {
+		return nil, fmt.Errorf("no git-checkout pipeline found in the Wolfi definition")
+	}
+
+	return remediateGoBumpDeps(doc, updated, tempDir, vulns)
+}
+
+// remediateGoBumpDeps does the work of RemediateGoBumpDeps against sources
+// that have already been checked out into srcDir.
+func remediateGoBumpDeps(doc *yaml.Node, updated *config.Configuration, srcDir string, vulns []models.Vulnerability) ([]ModuleFix, error) {
+	pipelineNode := findPipelineNode(doc)
+	if pipelineNode == nil {
+		return nil, fmt.Errorf("pipeline node not found in the Wolfi definition")
+	}
+
+	if !ContainsGoBumpPipeline(updated) {
+		idx := slices.IndexFunc(updated.Pipeline, func(p config.Pipeline) bool {
+			return p.Uses == "git-checkout"
+		})
+		if idx == -1 {
+			return nil, fmt.Errorf("no git-checkout pipeline found in the Wolfi definition")
+		}
+
+		p := config.Pipeline{Uses: "go/bump", With: map[string]string{}}
+		fixes, err := remediateGoBumpPipeline(&p, srcDir, vulns)
+		if err != nil {
+			return nil, err
+		}
+		if p.With["deps"] == "" {
+			return fixes, nil
+		}
+
+		updated.Pipeline = slices.Insert(updated.Pipeline, idx+1, p)
+		pipelineNode.Content = slices.Insert(pipelineNode.Content, idx+1, newGoBumpStep(p.With["deps"]))
+		return fixes, nil
+	}
+
+	var fixes []ModuleFix
+	for i := range updated.Pipeline {
+		if updated.Pipeline[i].Uses != "go/bump" {
+			continue
+		}
+
+		before := updated.Pipeline[i].With["deps"]
+		f, err := remediateGoBumpPipeline(&updated.Pipeline[i], srcDir, vulns)
+		if err != nil {
+			return nil, err
+		}
+		fixes = append(fixes, f...)
+
+		if updated.Pipeline[i].With["deps"] == before {
+			continue
+		}
+		if err := setGoBumpDeps(pipelineNode.Content[i], updated.Pipeline[i].With["deps"]); err != nil {
+			return nil, err
+		}
+	}
+
+	return fixes, nil
+}
+
+// remediateGoBumpPipeline updates the deps of a single go/bump pipeline to
+// cover the fixes needed by the go.mod in its modroot.
+func remediateGoBumpPipeline(p *config.Pipeline, srcDir string, vulns []models.Vulnerability) ([]ModuleFix, error) {
+	if p.With == nil {
+		p.With = map[string]string{}
+	}
+
+	modFile, err := parseGoModfile(path.Join(srcDir, p.With["modroot"], "go.mod"))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the go mod file with error: %v", err)
+	}
+
+	deps := []string{}
+	if p.With["deps"] != "" {
+		deps = strings.Split(p.With["deps"], " ")
+	}
+	bumped := map[string]string{}
+	for _, dep := range deps {
+		parts := strings.Split(dep, "@")
+		if len(parts) == 2 {
+			bumped[parts[0]] = parts[1]
+		}
+	}
+
+	fixes := FindGoModuleFixes(modFile, bumped, vulns)
+	for _, fix := range fixes {
+		if fix.FixedVersion == "" {
+			continue
+		}
+		deps = slices.DeleteFunc(deps, func(dep string) bool {
+			return strings.HasPrefix(dep, fix.Path+"@")
+		})
+		deps = append(deps, fmt.Sprintf("%s@%s", fix.Path, fix.FixedVersion))
+	}
+
+	p.With["deps"] = strings.TrimSpace(strings.Join(deps, " "))
+	log.Printf("New [deps]: %v\n", p.With["deps"])
+
+	return fixes, nil
+}
+
+// setGoBumpDeps sets the deps field of a go/bump step, adding it if needed.
+func setGoBumpDeps(stepNode *yaml.Node, deps string) error {
+	for i := 0; i < len(stepNode.Content); i += 2 {
+		if stepNode.Content[i].Value != "with" {
+			continue
+		}
+		withNode := stepNode.Content[i+1]
+		for j := 0; j < len(withNode.Content); j += 2 {
+			if withNode.Content[j].Value == "deps" {
+				depsNode := withNode.Content[j+1]
+				if depsNode.Kind != yaml.ScalarNode {
+					return fmt.Errorf("deps field is not a scalar")
+				}
+				depsNode.Value = deps
+				return nil
+			}
+		}
+		withNode.Content = append(withNode.Content, scalarNode("deps"), scalarNode(deps))
+		return nil
+	}
+
+	stepNode.Content = append(stepNode.Content, scalarNode("with"), &yaml.Node{
+		Kind:    yaml.MappingNode,
+		Content: []*yaml.Node{scalarNode("deps"), scalarNode(deps)},
+	})
+	return nil
+}
+
+func newGoBumpStep(deps string) *yaml.Node {
+	return &yaml.Node{
+		Kind: yaml.MappingNode,
+		Content: []*yaml.Node{
+			scalarNode("uses"), scalarNode("go/bump"),
+			scalarNode("with"), {
+				Kind:    yaml.MappingNode,
+				Content: []*yaml.Node{scalarNode("deps"), scalarNode(deps)},
+			},
+		},
+	}
+}
+
+func scalarNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
@@ -0,0 +1,188 @@
+package deps
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"chainguard.dev/melange/pkg/config"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/osv-scanner/pkg/models"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfi-dev/wolfictl/pkg/yam"
+	"gopkg.in/yaml.v3"
+)
+
+func loadTestVulns(t *testing.T) []models.Vulnerability {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join("testdata", "remediate", "osv.json"))
+	require.NoError(t, err)
+
+	var vulns []models.Vulnerability
+	require.NoError(t, json.Unmarshal(b, &vulns))
+	return vulns
+}
+
+func TestFindGoModuleFixes(t *testing.T) {
+	vulns := loadTestVulns(t)
+	modFile, err := parseGoModfile(filepath.Join("testdata", "remediate", "src", "go.mod"))
+	require.NoError(t, err)
+
+	testcases := []struct {
+		name   string
+		bumped map[string]string
+		want   []ModuleFix
+	}{{
+		name: "go.mod versions only",
+		want: []ModuleFix{{
+			Path:            "golang.org/x/crypto",
+			CurrentVersion:  "v0.14.0",
+			FixedVersion:    "v0.17.0",
+			Vulnerabilities: []string{"GO-2023-2402"},
+		}, {
+			Path:            "golang.org/x/net",
+			CurrentVersion:  "v0.17.0",
+			FixedVersion:    "v0.23.0",
+			Vulnerabilities: []string{"GO-2024-2687"},
+		}, {
+			Path:            "golang.org/x/text",
+			CurrentVersion:  "v0.13.0",
+			Vulnerabilities: []string{"GO-2099-0001"},
+			Unfixed:         []string{"GO-2099-0001"},
+		}},
+	}, {
+		name: "existing go/bump deps already fix a module",
+		bumped: map[string]string{
+			"golang.org/x/crypto": "v0.17.0",
+			"golang.org/x/net":    "v0.25.0",
+		},
+		want: []ModuleFix{{
+			Path:            "golang.org/x/text",
+			CurrentVersion:  "v0.13.0",
+			Vulnerabilities: []string{"GO-2099-0001"},
+			Unfixed:         []string{"GO-2099-0001"},
+		}},
+	}, {
+		name: "older go/bump deps are ignored",
+		bumped: map[string]string{
+			"golang.org/x/net": "v0.1.0",
+		},
+		want: []ModuleFix{{
+			Path:            "golang.org/x/crypto",
+			CurrentVersion:  "v0.14.0",
+			FixedVersion:    "v0.17.0",
+			Vulnerabilities: []string{"GO-2023-2402"},
+		}, {
+			Path:            "golang.org/x/net",
+			CurrentVersion:  "v0.17.0",
+			FixedVersion:    "v0.23.0",
+			Vulnerabilities: []string{"GO-2024-2687"},
+		}, {
+			Path:            "golang.org/x/text",
+			CurrentVersion:  "v0.13.0",
+			Vulnerabilities: []string{"GO-2099-0001"},
+			Unfixed:         []string{"GO-2099-0001"},
+		}},
+	}}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := FindGoModuleFixes(modFile, tc.bumped, vulns)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected fixes (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFixedVersionFor(t *testing.T) {
+	vuln := &models.Vulnerability{
+		ID: "GO-TEST",
+		Affected: []models.Affected{{
+			Package: models.Package{Ecosystem: models.EcosystemGo, Name: "example.com/mod"},
+			Ranges: []models.Range{{
+				Type: models.RangeSemVer,
+				Events: []models.Event{
+					{Introduced: "1.0.0"}, {Fixed: "1.2.3"},
+					{Introduced: "2.0.0"}, {Fixed: "2.1.0"},
+					{Introduced: "3.0.0"}, {LastAffected: "3.0.5"},
+				},
+			}},
+		}},
+	}
+
+	testcases := []struct {
+		version  string
+		fixed    string
+		affected bool
+	}{
+		{version: "v0.9.0"},
+		{version: "v1.0.0", fixed: "v1.2.3", affected: true},
+		{version: "v1.2.3"},
+		{version: "v2.0.5", fixed: "v2.1.0", affected: true},
+		{version: "v3.0.5", affected: true},
+		{version: "v3.0.6"},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.version, func(t *testing.T) {
+			fixed, affected := fixedVersionFor(vuln, "example.com/mod", tc.version)
+			require.Equal(t, tc.affected, affected)
+			require.Equal(t, tc.fixed, fixed)
+		})
+	}
+
+	_, affected := fixedVersionFor(vuln, "example.com/other", "v1.0.0")
+	require.False(t, affected)
+}
+
+func TestRemediateGoBumpDeps(t *testing.T) {
+	testcases := []struct {
+		name     string
+		filename string
+	}{{
+		name:     "update existing go/bump",
+		filename: "config-gobump",
+	}, {
+		name:     "add go/bump",
+		filename: "config-nogobump",
+	}}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := filepath.Join("testdata", "remediate")
+			tempDir := t.TempDir()
+			filename := tc.filename + ".yaml"
+
+			copyFile(t, filepath.Join(dir, filename), tempDir)
+			copyFile(t, filepath.Join("testdata", ".yam.yaml"), tempDir)
+
+			yamlContent, err := os.ReadFile(filepath.Join(tempDir, filename))
+			require.NoError(t, err)
+
+			expectedYAMLContent, err := os.ReadFile(filepath.Join(dir, tc.filename+"_expected.yaml"))
+			require.NoError(t, err)
+
+			updated, err := config.ParseConfiguration(context.Background(), filepath.Join(dir, filename))
+			require.NoError(t, err)
+
+			var doc yaml.Node
+			require.NoError(t, yaml.Unmarshal(yamlContent, &doc))
+
+			fixes, err := remediateGoBumpDeps(&doc, updated, filepath.Join(dir, "src"), loadTestVulns(t))
+			require.NoError(t, err)
+			require.Len(t, fixes, 3)
+
+			modifiedYAML, err := yaml.Marshal(&doc)
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(filepath.Join(tempDir, filename), modifiedYAML, 0o600))
+			require.NoError(t, yam.FormatConfigurationFile(tempDir, filename))
+
+			modifiedYAMLContent, err := os.ReadFile(filepath.Join(tempDir, filename))
+			require.NoError(t, err)
+
+			if diff := cmp.Diff(string(expectedYAMLContent), string(modifiedYAMLContent)); diff != "" {
+				t.Errorf("unexpected file modification results (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
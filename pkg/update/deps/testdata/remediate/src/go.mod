@@ -0,0 +1,14 @@
+module example.com/hello
+
+go 1.21
+
+require (
+	github.com/pkg/errors v0.9.1
+	golang.org/x/crypto v0.14.0
+	golang.org/x/net v0.17.0
+	google.golang.org/grpc v1.58.2
+)
+
+require golang.org/x/text v0.13.0 // indirect
+
+replace google.golang.org/grpc => google.golang.org/grpc v1.58.3
@@ -10,7 +10,6 @@ import (
 	"log"
 	"net/http"
 	"net/url"
-	"os"
 	"path"
 	"regexp"
 	"sort"
@@ -79,6 +78,8 @@ query {
           }
         }
         name
+        description
+        url
         isPrerelease
         isDraft
         isLatest
@@ -147,6 +148,8 @@ type Releases struct {
 				} `json:"target"`
 			} `json:"tag"`
 			Name         string `json:"name"`
+			Description  string `json:"description"`
+			URL          string `json:"url"`
 			IsPrerelease bool   `json:"isPrerelease"`
 			IsDraft      bool   `json:"isDraft"`
 			IsLatest     bool   `json:"isLatest"`
@@ -363,12 +366,13 @@ func (o GitHubReleaseOptions) get(requestQuery string) ([]byte, error) {
 		return nil, err
 	}
 
-	token := os.Getenv("GITHUB_TOKEN")
-	if token == "" {
+	tokens := http2.GitHubTokens()
+	if len(tokens) == 0 {
 		return nil, errors.New("no GITHUB_TOKEN environment variable found, required by GitHub GraphQL API.  Create Personal Access Token without any scopes https://github.com/settings/tokens/new")
 	}
 
-	req.Header.Add("Authorization", fmt.Sprintf("bearer %s", token))
+	// a GitHubTransport replaces this with the next token from its pool
+	req.Header.Add("Authorization", fmt.Sprintf("bearer %s", tokens[0]))
 	resp, err := o.GitHubHTTPClient.Do(req)
 	if err != nil {
 		return nil, err
@@ -405,6 +409,7 @@ func (o GitHubReleaseOptions) parseGitHubTags(repos *QueryTagsResponse) (results
 		// strip prefix that avoids github thinking hash is not a float
 		packageNameHash = strings.TrimPrefix(packageNameHash, "r")
 		versions := make(map[string]string)
+		releases := make(map[string]ReleaseNote)
 		c, ok := o.ConfigsByHash[packageNameHash]
 		if !ok {
 			return results, fmt.Errorf("no package config found for identifier %s", repo.NameWithOwner)
@@ -426,11 +431,13 @@ func (o GitHubReleaseOptions) parseGitHubTags(repos *QueryTagsResponse) (results
 				continue
 			}
 			versions[v] = commitSha
+			releases[v] = ReleaseNote{Tag: node.TagName}
 		}
 		err = o.getLatestVersion(packageNameHash, versions, repo.NameWithOwner, results)
 		if err != nil {
 			o.ErrorMessages[c.Package.Name] = err.Error()
 		}
+		addReleaseNotes(&c, repo.NameWithOwner, releases, results)
 	}
 
 	return results, nil
@@ -443,6 +450,7 @@ func (o GitHubReleaseOptions) parseGitHubReleases(repos *QueryReleasesResponse)
 		// strip prefix that avoids github thinking hash is not a float
 		packageNameHash = strings.TrimPrefix(packageNameHash, "r")
 		versions := make(map[string]string)
+		releases := make(map[string]ReleaseNote)
 
 		// compare if this version is newer than the version we have in our
 		// related melange package config
@@ -488,17 +496,69 @@ func (o GitHubReleaseOptions) parseGitHubReleases(repos *QueryReleasesResponse)
 			}
 
 			versions[v] = commitSha
+			releases[v] = ReleaseNote{Tag: tag, URL: release.URL, Description: release.Description}
 		}
 
 		err = o.getLatestVersion(packageNameHash, versions, node.NameWithOwner, results)
 		if err != nil {
 			o.ErrorMessages[c.Package.Name] = err.Error()
 		}
+		addReleaseNotes(&c, node.NameWithOwner, releases, results)
 	}
 
 	return results, nil
 }
 
+// addReleaseNotes adds the notes of the releases newer than the package's
+// current version, up to and including its latest version, and a link to
+// compare the current and latest tags.
+func addReleaseNotes(c *config.Configuration, nameWithOwner string, releases map[string]ReleaseNote, results map[string]NewVersionResults) {
+	r, ok := results[c.Package.Name]
+	if !ok {
+		return
+	}
+	current, err := wolfiversions.NewVersion(c.Package.Version)
+	if err != nil {
+		return
+	}
+	latest, err := wolfiversions.NewVersion(r.Version)
+	if err != nil || !latest.GreaterThan(current) {
+		return
+	}
+
+	type note struct {
+		v *version.Version
+		ReleaseNote
+	}
+	var notes []note
+	for v, rel := range releases {
+		if rel.Description == "" {
+			continue
+		}
+		sv, err := wolfiversions.NewVersion(v)
+		if err != nil {
+			continue
+		}
+		if sv.GreaterThan(current) && !sv.GreaterThan(latest) {
+			notes = append(notes, note{v: sv, ReleaseNote: rel})
+		}
+	}
+	sort.Slice(notes, func(i, j int) bool {
+		return notes[i].v.GreaterThan(notes[j].v)
+	})
+	for _, n := range notes {
+		r.ReleaseNotes = append(r.ReleaseNotes, n.ReleaseNote)
+	}
+
+	oldRelease, oldOK := releases[c.Package.Version]
+	newRelease, newOK := releases[r.Version]
+	if oldOK && newOK {
+		r.CompareURL = fmt.Sprintf("https://github.com/%s/compare/%s...%s", nameWithOwner, url.PathEscape(oldRelease.Tag), url.PathEscape(newRelease.Tag))
+	}
+
+	results[c.Package.Name] = r
+}
+
 func getCommit(commitURLStr string) (string, error) {
 	commitURL, err := url.Parse(commitURLStr)
 	if err != nil {
@@ -44,11 +44,15 @@ func TestMonitorService_parseGitHubReleases(t *testing.T) {
 		initialVersion  string
 		expectedVersion string
 		githubMonitor   config.GitHubMonitor
+		expectedNotes   []string
+		expectedCompare string
 	}{
 		{
 			name:            "multiple_repos",
 			packageName:     "cosign",
 			expectedVersion: "2.0.0",
+			expectedNotes:   []string{"v2.0.0", "v1.13.1"},
+			expectedCompare: "https://github.com/sigstore/cosign/compare/v1.10.1...v2.0.0",
 		},
 		{
 			name:            "multiple_repos",
@@ -77,6 +81,13 @@ func TestMonitorService_parseGitHubReleases(t *testing.T) {
 			assert.NoError(t, err)
 			assert.Empty(t, errorMessages)
 			assert.Equal(t, test.expectedVersion, latestVersions[test.packageName].Version)
+
+			var notes []string
+			for _, n := range latestVersions[test.packageName].ReleaseNotes {
+				notes = append(notes, n.Tag)
+			}
+			assert.Equal(t, test.expectedNotes, notes)
+			assert.Equal(t, test.expectedCompare, latestVersions[test.packageName].CompareURL)
 		})
 	}
 }
@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"chainguard.dev/melange/pkg/config"
 	http2 "github.com/wolfi-dev/wolfictl/pkg/http"
 
 	"github.com/wolfi-dev/wolfictl/pkg/melange"
@@ -72,7 +73,7 @@ ReleaseMonitorPackagesLoop:
 
 		m.Logger.Printf("[%d/%d] %s: checking release monitor using id %d\n", count, size, packageName, rm.Identifier)
 
-		latestVersion, err := m.getLatestReleaseVersion(rm.Identifier)
+		stableVersions, err := m.getStableReleaseVersions(rm.Identifier)
 		if err != nil {
 			errorMessages[p.Config.Package.Name] = fmt.Sprintf(
 				"failed getting latest release version for package %s, identifier %d: %s",
@@ -80,11 +81,17 @@ ReleaseMonitorPackagesLoop:
 			)
 			continue
 		}
+
+		stream := versionStream(&p.Config.Package)
+		latestVersion := latestInStream(stableVersions, stream, func(v string) string {
+			return normalizeReleaseMonitorVersion(&p.Config.Update, v)
+		})
 		if latestVersion == "" {
-			errorMessages[p.Config.Package.Name] = fmt.Sprintf(
-				"no latest version found in release monitor for package %s, identifier %d",
-				p.Config.Package.Name, rm.Identifier,
-			)
+			msg := fmt.Sprintf("no latest version found in release monitor for package %s, identifier %d", p.Config.Package.Name, rm.Identifier)
+			if stream != "" {
+				msg += fmt.Sprintf(" in version stream %s", stream)
+			}
+			errorMessages[p.Config.Package.Name] = msg
 			continue
 		}
 
@@ -103,18 +110,7 @@ ReleaseMonitorPackagesLoop:
 			}
 		}
 
-		// replace any nonstandard version separators
-		if p.Config.Update.VersionSeparator != "" {
-			latestVersion = strings.ReplaceAll(latestVersion, p.Config.Update.VersionSeparator, ".")
-		}
-
-		if p.Config.Update.ReleaseMonitor.StripPrefix != "" {
-			latestVersion = strings.TrimPrefix(latestVersion, p.Config.Update.ReleaseMonitor.StripPrefix)
-		}
-
-		if p.Config.Update.ReleaseMonitor.StripSuffix != "" {
-			latestVersion = strings.TrimSuffix(latestVersion, p.Config.Update.ReleaseMonitor.StripSuffix)
-		}
+		latestVersion = normalizeReleaseMonitorVersion(&p.Config.Update, latestVersion)
 
 		latestVersion, err = transformVersion(p.Config.Update, latestVersion)
 		if err != nil {
@@ -138,7 +134,56 @@ ReleaseMonitorPackagesLoop:
 	return packagesToUpdate, errorMessages
 }
 
-func (m MonitorService) getLatestReleaseVersion(identifier int) (string, error) {
+// normalizeReleaseMonitorVersion replaces nonstandard version separators and
+// strips the configured prefix and suffix from a release monitor version.
+func normalizeReleaseMonitorVersion(u *config.Update, v string) string {
+	if u.VersionSeparator != "" {
+		v = strings.ReplaceAll(v, u.VersionSeparator, ".")
+	}
+	if u.ReleaseMonitor.StripPrefix != "" {
+		v = strings.TrimPrefix(v, u.ReleaseMonitor.StripPrefix)
+	}
+	if u.ReleaseMonitor.StripSuffix != "" {
+		v = strings.TrimSuffix(v, u.ReleaseMonitor.StripSuffix)
+	}
+	return v
+}
+
+var reVersionStream = regexp.MustCompile(`^\d+(\.\d+)*$`)
+
+// versionStream returns the version stream tracked by a package, or an empty
+// string if it tracks the latest release. Packages that maintain an older
+// release line are named after the stream, e.g. postgresql-15 at version
+// 15.6 or go-1.21 at version 1.21.8.
+func versionStream(pkg *config.Package) string {
+	i := strings.LastIndex(pkg.Name, "-")
+	if i == -1 {
+		return ""
+	}
+	stream := pkg.Name[i+1:]
+	if !reVersionStream.MatchString(stream) {
+		return ""
+	}
+	if pkg.Version != stream && !strings.HasPrefix(pkg.Version, stream+".") {
+		return ""
+	}
+	return stream
+}
+
+// latestInStream returns the first of the versions, which are ordered newest
+// first, that belongs to the stream once normalized. Any version matches an
+// empty stream.
+func latestInStream(versions []string, stream string, normalize func(string) string) string {
+	for _, v := range versions {
+		n := normalize(v)
+		if stream == "" || n == stream || strings.HasPrefix(n, stream+".") {
+			return v
+		}
+	}
+	return ""
+}
+
+func (m MonitorService) getStableReleaseVersions(identifier int) ([]string, error) {
 	targetURL := fmt.Sprintf(releaseMonitorURL, identifier)
 	var err error
 
@@ -150,21 +195,21 @@ func (m MonitorService) getLatestReleaseVersion(identifier int) (string, error)
 	for i := 0; i < maxRetries; i++ {
 		req, err := http.NewRequest("GET", targetURL, nil)
 		if err != nil {
-			return "", fmt.Errorf("failed creating GET request %s: %w", targetURL, err)
+			return nil, fmt.Errorf("failed creating GET request %s: %w", targetURL, err)
 		}
 
 		resp, err := m.Client.Do(req)
 		if err != nil {
-			return "", fmt.Errorf("failed getting URI %s: %w", targetURL, err)
+			return nil, fmt.Errorf("failed getting URI %s: %w", targetURL, err)
 		}
 		defer resp.Body.Close()
 
 		if resp.StatusCode == http.StatusOK {
 			b, err := io.ReadAll(resp.Body)
 			if err != nil {
-				return "", fmt.Errorf("reading monitor service mapper data file: %w", err)
+				return nil, fmt.Errorf("reading monitor service mapper data file: %w", err)
 			}
-			return m.parseVersions(b)
+			return m.parseStableVersions(b)
 		}
 
 		// Check if the status code is a 500 or 503, then retry
@@ -175,21 +220,23 @@ func (m MonitorService) getLatestReleaseVersion(identifier int) (string, error)
 			continue // Retry
 		}
 
-		return "", fmt.Errorf("non ok http response for URI %s code: %v", targetURL, resp.StatusCode)
+		return nil, fmt.Errorf("non ok http response for URI %s code: %v", targetURL, resp.StatusCode)
 	}
 
-	return "", fmt.Errorf("max retries reached; last error: %v", err)
+	return nil, fmt.Errorf("max retries reached; last error: %v", err)
 }
 
-func (m MonitorService) parseVersions(rawdata []byte) (string, error) {
+// parseStableVersions returns the stable versions from release monitor data,
+// newest first.
+func (m MonitorService) parseStableVersions(rawdata []byte) ([]string, error) {
 	versions := ReleaseMonitorVersions{}
 	err := json.Unmarshal(rawdata, &versions)
 	if err != nil {
-		return "", fmt.Errorf("unmarshalling version data: %w", err)
+		return nil, fmt.Errorf("unmarshalling version data: %w", err)
 	}
 
 	if len(versions.StableVersions) == 0 {
-		return "", fmt.Errorf("no stable version found: %w", err)
+		return nil, fmt.Errorf("no stable version found: %w", err)
 	}
-	return versions.StableVersions[0], nil
+	return versions.StableVersions, nil
 }
@@ -6,10 +6,11 @@ import (
 	"path/filepath"
 	"testing"
 
+	"chainguard.dev/melange/pkg/config"
 	"github.com/stretchr/testify/assert"
 )
 
-func TestReleaseMonitor_parseVersions(t *testing.T) {
+func TestReleaseMonitor_parseStableVersions(t *testing.T) {
 	m := MonitorService{Logger: log.New(log.Writer(), "test: ", log.LstdFlags|log.Lmsgprefix)}
 
 	tests := []struct {
@@ -24,10 +25,43 @@ func TestReleaseMonitor_parseVersions(t *testing.T) {
 		assert.NoError(t, err)
 
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := m.parseVersions(data)
+			got, err := m.parseStableVersions(data)
 			assert.NoError(t, err)
-			assert.Equalf(t, tt.expectedLatestVersion, got, "parseVersions(%v)", tt.name)
-			assert.Equalf(t, tt.expectedLatestVersion, got, "parseVersions(%v)", tt.name)
+			assert.Equalf(t, tt.expectedLatestVersion, got[0], "parseStableVersions(%v)", tt.name)
 		})
 	}
 }
+
+func TestReleaseMonitor_versionStream(t *testing.T) {
+	tests := []struct {
+		name, version, expected string
+	}{
+		{name: "postgresql-15", version: "15.6", expected: "15"},
+		{name: "go-1.21", version: "1.21.8", expected: "1.21"},
+		{name: "openjdk-17", version: "17", expected: "17"},
+		{name: "postgresql", version: "16.2", expected: ""},
+		{name: "py3-setuptools", version: "69.1.1", expected: ""},
+		{name: "gtk-4", version: "3.24.41", expected: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, versionStream(&config.Package{Name: tt.name, Version: tt.version}))
+		})
+	}
+}
+
+func TestReleaseMonitor_latestInStream(t *testing.T) {
+	stable := []string{"17.0", "16.2", "16.1", "15.6", "15.5", "1.5"}
+	identity := func(v string) string { return v }
+
+	assert.Equal(t, "17.0", latestInStream(stable, "", identity))
+	assert.Equal(t, "16.2", latestInStream(stable, "16", identity))
+	assert.Equal(t, "15.6", latestInStream(stable, "15", identity))
+	assert.Equal(t, "", latestInStream(stable, "14", identity))
+
+	icu := []string{"74-2", "74-1", "73-2", "72-1"}
+	u := &config.Update{VersionSeparator: "-", ReleaseMonitor: &config.ReleaseMonitor{}}
+	assert.Equal(t, "73-2", latestInStream(icu, "73", func(v string) string {
+		return normalizeReleaseMonitorVersion(u, v)
+	}))
+}
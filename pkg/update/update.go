@@ -29,7 +29,6 @@ import (
 	"github.com/wolfi-dev/wolfictl/pkg/update/deps"
 	wolfiversions "github.com/wolfi-dev/wolfictl/pkg/versions"
 	"golang.org/x/exp/maps"
-	"golang.org/x/oauth2"
 	"golang.org/x/time/rate"
 	"gopkg.in/yaml.v3"
 )
@@ -63,6 +62,17 @@ type NewVersionResults struct {
 	ReplaceExistingIssueNumber int
 	ReplaceExistingPRNumber    int
 	BumpEpoch                  bool
+	// ReleaseNotes are the upstream releases since the current version, newest first
+	ReleaseNotes []ReleaseNote
+	// CompareURL links to the upstream changes between the current and new versions
+	CompareURL string
+}
+
+// ReleaseNote is the notes of an upstream release.
+type ReleaseNote struct {
+	Tag         string
+	URL         string
+	Description string
 }
 
 const (
@@ -75,11 +85,15 @@ const (
 `
 )
 
+// limits that keep the pull request body well within GitHub's maximum size
+const (
+	maxReleaseNotes      = 10
+	maxReleaseNoteLength = 2000
+)
+
 // New initialise including a map of existing wolfios packages
-func New(ctx context.Context) Options {
-	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: os.Getenv("GITHUB_TOKEN")},
-	)
+func New(_ context.Context) Options {
+	logger := log.New(log.Writer(), "wolfictl update: ", log.LstdFlags|log.Lmsgprefix)
 	token := os.Getenv("RELEASE_MONITOR_TOKEN")
 
 	var rateLimitDuration time.Duration
@@ -103,12 +117,15 @@ func New(ctx context.Context) Options {
 		},
 
 		GitHubHTTPClient: &http2.RLHTTPClient{
-			Client: oauth2.NewClient(ctx, ts),
+			// tokens are used in turn so a large batch of packages does not exhaust the rate limit of a single token
+			Client: &http.Client{
+				Transport: http2.NewGitHubTransport(http.DefaultTransport, http2.GitHubTokens(), logger),
+			},
 
 			// 1 request every (n) second(s) to avoid DOS'ing server. https://docs.github.com/en/rest/guides/best-practices-for-integrators?apiVersion=2022-11-28#dealing-with-secondary-rate-limits
 			Ratelimiter: rate.NewLimiter(rate.Every(5*time.Second), 1),
 		},
-		Logger:        log.New(log.Writer(), "wolfictl update: ", log.LstdFlags|log.Lmsgprefix),
+		Logger:        logger,
 		DefaultBranch: "main",
 		ErrorMessages: make(map[string]string),
 	}
@@ -211,6 +228,11 @@ func (o *Options) Update(ctx context.Context) error {
 		}
 	}
 
+	if t, ok := o.GitHubHTTPClient.Client.Transport.(*http2.GitHubTransport); ok {
+		stats := t.Stats()
+		o.Logger.Printf("made %d GitHub requests, %d were rate limited, waited %s for rate limits to reset", stats.Requests, stats.RateLimited, stats.Waited)
+	}
+
 	return nil
 }
 
@@ -630,7 +652,7 @@ func (o *Options) proposeChanges(ctx context.Context, repo *git.Repository, ref
 	newPR := &gh.NewPullRequest{
 		BasePullRequest: basePullRequest,
 		Title:           title,
-		Body:            wolfiImage,
+		Body:            o.pullRequestBody(packageName, newVersion),
 	}
 
 	// create the pull request
@@ -659,6 +681,66 @@ func (o *Options) proposeChanges(ctx context.Context, repo *git.Repository, ref
 	return prLink, nil
 }
 
+// pullRequestBody describes the package update so reviewers can see what changed without reading the diff
+func (o *Options) pullRequestBody(packageName string, newVersion NewVersionResults) string {
+	var sb strings.Builder
+	sb.WriteString(wolfiImage)
+	sb.WriteString("\n## Package update\n\n")
+	sb.WriteString("| | |\n|---|---|\n")
+	fmt.Fprintf(&sb, "| Package | `%s` |\n", packageName)
+
+	if pc, ok := o.PackageConfigs[packageName]; ok {
+		fmt.Fprintf(&sb, "| Previous version | `%s` |\n", pc.Config.Package.Version)
+	}
+	if newVersion.Version != "" {
+		fmt.Fprintf(&sb, "| New version | `%s` |\n", newVersion.Version)
+	}
+	if newVersion.Commit != "" {
+		fmt.Fprintf(&sb, "| Expected commit | `%s` |\n", newVersion.Commit)
+	}
+
+	if newVersion.BumpEpoch {
+		sb.WriteString("\nThe upstream tag now points at a different commit, so the expected commit was updated and the epoch bumped.\n")
+	}
+	if newVersion.ReplaceExistingPRNumber != 0 {
+		fmt.Fprintf(&sb, "\nSupersedes #%d.\n", newVersion.ReplaceExistingPRNumber)
+	}
+	if newVersion.CompareURL != "" {
+		fmt.Fprintf(&sb, "\n[Compare upstream changes](%s)\n", newVersion.CompareURL)
+	}
+
+	if len(newVersion.ReleaseNotes) > 0 {
+		sb.WriteString("\n## Release notes\n")
+		for i, n := range newVersion.ReleaseNotes {
+			if i == maxReleaseNotes {
+				fmt.Fprintf(&sb, "\n%d older releases not shown.\n", len(newVersion.ReleaseNotes)-maxReleaseNotes)
+				break
+			}
+			fmt.Fprintf(&sb, "\n<details>\n<summary>%s</summary>\n\n%s\n", n.Tag, truncateReleaseNote(n.Description))
+			if n.URL != "" {
+				fmt.Fprintf(&sb, "\n[View release](%s)\n", n.URL)
+			}
+			sb.WriteString("</details>\n")
+		}
+	}
+
+	return sb.String()
+}
+
+// truncateReleaseNote shortens long release notes, cutting at a line break
+// where possible.
+func truncateReleaseNote(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) <= maxReleaseNoteLength {
+		return s
+	}
+	cut := s[:maxReleaseNoteLength]
+	if i := strings.LastIndex(cut, "\n"); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.ToValidUTF8(cut, "") + "\n\n…"
+}
+
 // commit changes to git
 func (o *Options) commitChanges(repo *git.Repository, packageName, latestVersion string) error {
 	worktree, err := repo.Worktree()
@@ -287,3 +287,89 @@ func TestOptions_getPackagesToUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestOptions_pullRequestBody(t *testing.T) {
+	o := &Options{
+		PackageConfigs: map[string]*melange.Packages{
+			"foo": {
+				Config: config.Configuration{
+					Package: config.Package{
+						Name:    "foo",
+						Version: "1.0.0",
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		newVersion NewVersionResults
+		want       []string
+		notWant    []string
+	}{
+		{
+			name:       "new version",
+			newVersion: NewVersionResults{Version: "2.0.0", Commit: "4444444444"},
+			want: []string{
+				"| Package | `foo` |",
+				"| Previous version | `1.0.0` |",
+				"| New version | `2.0.0` |",
+				"| Expected commit | `4444444444` |",
+			},
+			notWant: []string{"epoch bumped", "Supersedes"},
+		},
+		{
+			name:       "moved tag",
+			newVersion: NewVersionResults{Version: "1.0.0", Commit: "4444444444", BumpEpoch: true},
+			want:       []string{"| New version | `1.0.0` |", "epoch bumped"},
+		},
+		{
+			name:       "release monitor without commit supersedes an existing pull request",
+			newVersion: NewVersionResults{Version: "2.0.0", ReplaceExistingPRNumber: 42},
+			want:       []string{"| New version | `2.0.0` |", "Supersedes #42."},
+			notWant:    []string{"Expected commit"},
+		},
+		{
+			name: "release notes",
+			newVersion: NewVersionResults{
+				Version:    "2.0.0",
+				CompareURL: "https://github.com/foo/foo/compare/v1.0.0...v2.0.0",
+				ReleaseNotes: []ReleaseNote{
+					{Tag: "v2.0.0", URL: "https://github.com/foo/foo/releases/tag/v2.0.0", Description: "breaking changes"},
+					{Tag: "v1.1.0", Description: strings.Repeat("fix\n", maxReleaseNoteLength)},
+				},
+			},
+			want: []string{
+				"[Compare upstream changes](https://github.com/foo/foo/compare/v1.0.0...v2.0.0)",
+				"## Release notes",
+				"<summary>v2.0.0</summary>\n\nbreaking changes\n",
+				"[View release](https://github.com/foo/foo/releases/tag/v2.0.0)",
+				"<summary>v1.1.0</summary>",
+				"fix\n\n…",
+			},
+			notWant: []string{"older releases not shown"},
+		},
+		{
+			name: "too many release notes",
+			newVersion: NewVersionResults{
+				Version:      "2.0.0",
+				ReleaseNotes: make([]ReleaseNote, maxReleaseNotes+2),
+			},
+			want:    []string{"2 older releases not shown."},
+			notWant: []string{"Compare upstream changes"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := o.pullRequestBody("foo", tt.newVersion)
+			assert.True(t, strings.HasPrefix(got, wolfiImage))
+			for _, w := range tt.want {
+				assert.Contains(t, got, w)
+			}
+			for _, w := range tt.notWant {
+				assert.NotContains(t, got, w)
+			}
+		})
+	}
+}